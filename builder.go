@@ -0,0 +1,400 @@
+package htmltable
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// section is the row group a table row belongs to
+type section int
+
+const (
+	sectionHead section = iota
+	sectionBody
+	sectionFoot
+)
+
+var sections = map[string]section{
+	"thead": sectionHead,
+	"tbody": sectionBody,
+	"tfoot": sectionFoot,
+}
+
+type row struct {
+	cells   []*Cell
+	section section
+	group   int
+}
+
+// tableBuilder accumulates rows of a single table element
+type tableBuilder struct {
+	// position of the table in Page.Tables
+	index    int
+	children []*Table
+
+	node    *html.Node
+	caption string
+	heading string
+	id      string
+	class   string
+
+	section section
+	group   int
+
+	// columns declared by <colgroup> and <col> elements
+	columns   []Column
+	colgroups int
+
+	// nil, unless <tr> is being parsed
+	row  []*Cell
+	rows []row
+	// cell being parsed, which gets nested tables
+	cell *Cell
+}
+
+// startGroup begins the row group of thead, tbody or tfoot element,
+// or the group of rows outside of it
+func (t *tableBuilder) startGroup(s section) {
+	t.finishRow()
+	t.section = s
+	t.group++
+}
+
+func (t *tableBuilder) startRow() {
+	t.finishRow()
+	t.row = []*Cell{}
+}
+
+func (t *tableBuilder) finishRow() {
+	if t.row == nil {
+		return
+	}
+	t.rows = append(t.rows, row{
+		cells:   t.row,
+		section: t.section,
+		group:   t.group,
+	})
+	t.row = nil
+}
+
+func (t *tableBuilder) addCell(c *Cell) {
+	if t.row == nil {
+		// cell outside of <tr> starts the row
+		t.startRow()
+	}
+	t.row = append(t.row, c)
+}
+
+func (r *row) allHeaders() bool {
+	if r == nil || len(r.cells) == 0 {
+		return false
+	}
+	for _, c := range r.cells {
+		if !c.Header {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *row) hasColSpans() bool {
+	for _, c := range r.cells {
+		if c.ColSpan > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// markRowHeader marks the leading <th> without scope as the row header,
+// if the row also has <td> cells
+func (r *row) markRowHeader() {
+	if len(r.cells) == 0 || r.allHeaders() {
+		return
+	}
+	c := r.cells[0]
+	if c.Header && c.Attrs["scope"] == "" {
+		c.RowHeader = true
+	}
+}
+
+// groupLabel returns the cell, that spans the whole grid row as the only
+// cell of the row, like "Mobile chipsets", or nil
+func (g *grid) groupLabel(y int) *Cell {
+	r := g.rows[y]
+	if r.section != sectionBody || len(r.cells) != 1 || r.cells[0].Text == "" || g.width < 2 {
+		return nil
+	}
+	for _, c := range g.slots[y] {
+		if c != r.cells[0] {
+			return nil
+		}
+	}
+	return r.cells[0]
+}
+
+// repeatsHeader is true for body rows of only <th> cells, and for rows, that
+// have the same text as one of the header levels or the header itself
+func (g *grid) repeatsHeader(y int, levels [][]string, header []string) bool {
+	if g.rows[y].allHeaders() && g.groupLabel(y) == nil {
+		return true
+	}
+	row := normalizeAll(values(g.slots[y]))
+	for _, level := range append(levels[:len(levels):len(levels)], header) {
+		if reflect.DeepEqual(row, normalizeAll(level)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeAll lowercases values and collapses whitespace within them
+func normalizeAll(values []string) []string {
+	normalized := make([]string, len(values))
+	for i, v := range values {
+		normalized[i] = strings.ToLower(strings.Join(strings.Fields(v), " "))
+	}
+	return normalized
+}
+
+// isDivider is true for rows, that most likely are empty table dividers
+func (r *row) isDivider() bool {
+	return len(r.cells) == 1 && r.cells[0].Text == ""
+}
+
+// headerRows returns the number of leading grid rows, that form the header:
+// rows from <thead>, leading rows of only <th> cells, or the first row.
+// Header cells spanning more rows pull those rows into the header as well.
+func (g *grid) headerRows(mode HeaderMode) int {
+	if len(g.slots) == 0 || mode == HeaderNever {
+		return 0
+	}
+	n := 0
+	for _, s := range g.sections {
+		if s != sectionHead {
+			break
+		}
+		n++
+	}
+	if n > 0 {
+		return n
+	}
+	for _, r := range g.rows {
+		if !r.allHeaders() {
+			break
+		}
+		n++
+	}
+	if n == 0 && mode == HeaderAuto {
+		return 0
+	}
+	if n == 0 && len(g.slots) > 1 && g.rows[0] != nil && g.rows[0].hasColSpans() {
+		// first row of <td> cells with colspans is most likely
+		// the upper level of the header
+		n = 2
+	} else if n == 0 {
+		n = 1
+	}
+	for n < len(g.slots) && g.spansFromAbove(n) {
+		n++
+	}
+	return n
+}
+
+// spansFromAbove is true if any cell in the row is anchored in the rows above
+func (g *grid) spansFromAbove(y int) bool {
+	for _, c := range g.slots[y] {
+		if c != nil && c.Row < y {
+			return true
+		}
+	}
+	return false
+}
+
+// form resolves spans of all rows parsed so far
+func (t *tableBuilder) form() *grid {
+	t.finishRow()
+	// thead may come in any order in the source, though as rows never
+	// span across row groups, moving the whole group keeps the grid intact
+	sort.SliceStable(t.rows, func(i, j int) bool {
+		return t.rows[i].section < t.rows[j].section
+	})
+	g := formTable(t.rows)
+	if len(t.columns) > g.width {
+		// declared columns make the grid wider than the cells would
+		g.width = len(t.columns)
+		for y := range g.slots {
+			g.fill(y)
+		}
+	}
+	return g
+}
+
+func (t *tableBuilder) finish(ctx context.Context, opts *options) (table *Table) {
+	defer func() {
+		if r := recover(); r != nil {
+			firstRow := []string{}
+			if len(t.rows) > 0 {
+				for _, c := range t.rows[0].cells {
+					firstRow = append(firstRow, c.Text)
+				}
+			}
+			Logger(ctx, "unparsable table", "panic", fmt.Sprintf("%v", r), "firstRow", firstRow)
+			table = nil
+		}
+	}()
+	g := t.form()
+	if len(g.slots) == 0 {
+		return nil
+	}
+	headerRows := g.headerRows(opts.header)
+	columns := make([]Column, g.width)
+	copy(columns, t.columns)
+	for x := range columns {
+		if columns[x].Attrs == nil {
+			columns[x].Attrs = map[string]string{}
+		}
+	}
+	g.labelGroups(columns, headerRows)
+	levels := [][]string{}
+	for _, level := range g.slots[:headerRows] {
+		levels = append(levels, values(level))
+	}
+	header := mergeHeader(g.slots[:headerRows])
+	if headerRows == 0 {
+		// columns of tables without header are named by their position
+		header = make([]string, g.width)
+		for x := range header {
+			header[x] = strconv.Itoa(x)
+		}
+	}
+	rows, cells := [][]string{}, [][]*Cell{}
+	footer, footerCells := [][]string{}, [][]*Cell{}
+	groups, groupCells := []string{}, []*Cell{}
+	group, lastGroup := "", -1
+	for y := headerRows; y < len(g.slots); y++ {
+		r := g.rows[y]
+		if r == nil || len(r.cells) == 0 || r.isDivider() {
+			// rows without own cells only hold cells spanning from above
+			continue
+		}
+		if r.section == sectionBody && headerRows > 0 && !opts.repeatedHeaders && g.repeatsHeader(y, levels, header) {
+			// long tables repeat the header every so many rows
+			continue
+		}
+		if r.section == sectionFoot {
+			footer = append(footer, values(g.slots[y]))
+			footerCells = append(footerCells, g.slots[y])
+			continue
+		}
+		if r.group != lastGroup {
+			// every <tbody> starts a new group
+			group, lastGroup = "", r.group
+		}
+		if label := g.groupLabel(y); label != nil {
+			group = label.Text
+			groupCells = append(groupCells, label)
+			continue
+		}
+		first := r.cells[0]
+		if first.RowHeader && strings.ToLower(first.Attrs["scope"]) == "rowgroup" {
+			group = first.Text
+		}
+		r.markRowHeader()
+		rows = append(rows, values(g.slots[y]))
+		cells = append(cells, g.slots[y])
+		groups = append(groups, group)
+	}
+	g.assignHeaders(header, headerRows)
+	labels := make([]string, len(cells))
+	for y, slots := range cells {
+		labels[y] = rowLabel(slots)
+	}
+	headerCells := g.slots[:headerRows]
+	if opts.groupColumn != "" {
+		// group becomes the last column of the table
+		header = append(header, opts.groupColumn)
+		columns = append(columns, Column{Attrs: map[string]string{}})
+		headerCells = make([][]*Cell, headerRows)
+		for y, level := range g.slots[:headerRows] {
+			levels[y] = append(levels[y], opts.groupColumn)
+			headerCells[y] = append(level[:len(level):len(level)], nil)
+		}
+		for y := range rows {
+			rows[y] = append(rows[y], groups[y])
+			cells[y] = append(cells[y], nil)
+		}
+	}
+	Logger(ctx, "found table", "columns", header, "count", len(rows))
+	return &Table{
+		Header:       header,
+		HeaderLevels: levels,
+		HeaderCells:  headerCells,
+		Columns:      columns,
+		Rows:         rows,
+		RowLabels:    labels,
+		Groups:       groups,
+		Cells:        cells,
+		Footer:       footer,
+		FooterCells:  footerCells,
+		Children:     t.children,
+		Caption:      t.caption,
+		Heading:      t.heading,
+		ID:           t.id,
+		Class:        t.class,
+		node:         t.node,
+		groupCells:   groupCells,
+	}
+}
+
+// values returns text of every slot in the grid row
+func values(slots []*Cell) []string {
+	values := make([]string, len(slots))
+	for x, c := range slots {
+		if c == nil {
+			continue
+		}
+		values[x] = c.Text
+	}
+	return values
+}
+
+// rowLabel returns text of the first row header in the grid row
+func rowLabel(slots []*Cell) string {
+	for _, c := range slots {
+		if c != nil && c.RowHeader {
+			return c.Text
+		}
+	}
+	return ""
+}
+
+// mergeHeader flattens any number of header rows into names like
+// "Added Ticker", where the same cell spanning more than one row is
+// mentioned only once and empty cells are omitted.
+func mergeHeader(levels [][]*Cell) []string {
+	if len(levels) == 0 {
+		return []string{}
+	}
+	header := make([]string, len(levels[0]))
+	for x := range header {
+		names := []string{}
+		var prev *Cell
+		for _, level := range levels {
+			c := level[x]
+			if c == nil || c == prev || c.Text == "" {
+				continue
+			}
+			prev = c
+			names = append(names, c.Text)
+		}
+		header[x] = strings.Join(names, " ")
+	}
+	return header
+}
@@ -0,0 +1,156 @@
+package htmltable
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Cell is the <td> or <th> element of the table
+type Cell struct {
+	// Text is the value of the cell, as it appears in Table.Rows
+	Text string
+
+	// HTML holds the original markup within the element
+	HTML string
+
+	// Links holds all <a href> elements within the cell
+	Links []Link
+
+	// Attrs holds attributes of the element, like class or data-*
+	Attrs map[string]string
+
+	// Header is true for <th> elements
+	Header bool
+
+	// RowHeader is true for <th scope="row"> elements and for <th> elements
+	// without scope, that start body rows of <td> cells
+	RowHeader bool
+
+	// ColSpan and RowSpan are spans of the cell, as parsed by the rules
+	// of HTML. RowSpan of zero spans till the end of the row group.
+	ColSpan int
+	RowSpan int
+
+	// Row and Col are the position of the top-left slot, that the cell
+	// covers in the table grid, where header rows come first.
+	Row int
+	Col int
+
+	// Headers holds text of header cells, that the cell belongs to: either
+	// referenced by ids in the headers attribute, or the column headers
+	// above and row headers to the left of the cell.
+	Headers []string
+
+	// Tables holds tables nested within the cell
+	Tables []*Table
+
+	// Footnotes holds references to footnotes or citations, like [4]
+	Footnotes []Footnote
+
+	// SortValue holds the machine-readable value of the cell from
+	// data-sort-value attribute of the cell or an element within it
+	SortValue string
+
+	node *html.Node
+}
+
+// Link is the <a> element within the cell
+type Link struct {
+	Href  string
+	Title string
+	Text  string
+}
+
+func (c *Cell) String() string {
+	return c.Text
+}
+
+// Attr returns the value of the attribute of the cell element
+func (c *Cell) Attr(key string) string {
+	return c.Attrs[key]
+}
+
+// findAttr returns the attribute of the cell element, or of the first
+// element within the cell, that has it, like href of the link.
+func (c *Cell) findAttr(key string) string {
+	if v, ok := c.Attrs[key]; ok {
+		return v
+	}
+	found := findElement(c.node, func(n *html.Node) bool {
+		for _, a := range n.Attr {
+			if a.Key == key {
+				return true
+			}
+		}
+		return false
+	})
+	return attr(found, key)
+}
+
+// imgAttr returns the attribute of the first <img> within the cell
+func (c *Cell) imgAttr(key string) string {
+	found := findElement(c.node, func(n *html.Node) bool {
+		return n.Data == "img"
+	})
+	return attr(found, key)
+}
+
+// findElement returns the first element within the node, that is not
+// within a nested table, and for which the match is true
+func findElement(n *html.Node, match func(n *html.Node) bool) *html.Node {
+	if n == nil {
+		return nil
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.Data == "table" {
+			continue
+		}
+		if match(c) {
+			return c
+		}
+		if found := findElement(c, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func (p *Page) innerHTML(n *html.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		// writing to strings.Builder never fails
+		_ = html.Render(&sb, c)
+	}
+	return sb.String()
+}
+
+// links returns all <a href> elements within the node
+func (p *Page) links(n *html.Node) []Link {
+	links := []Link{}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "table" {
+			// links of nested tables belong to their cells
+			return
+		}
+		if n.Type == html.ElementNode && n.Data == "a" {
+			if href := attr(n, "href"); href != "" {
+				var sb strings.Builder
+				p.innerText(n, &sb)
+				links = append(links, Link{
+					Href:  href,
+					Title: attr(n, "title"),
+					Text:  sb.String(),
+				})
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c)
+	}
+	return links
+}
@@ -0,0 +1,50 @@
+package htmltable
+
+import (
+	"testing"
+)
+
+func TestCells(t *testing.T) {
+	p, err := NewFromString(fixtureColspans)
+	assertNoError(t, err)
+	table := p.Tables[0]
+	assertEqual(t, len(table.Rows), len(table.Cells))
+
+	security := table.Cells[0][2]
+	assertEqual(t, "Keurig Dr Pepper", security.Text)
+	assertEqual(t, `<a href="/wiki/Keurig_Dr_Pepper" title="Keurig Dr Pepper">Keurig Dr Pepper</a>`, security.HTML)
+	assertEqual(t, []Link{{
+		Href:  "/wiki/Keurig_Dr_Pepper",
+		Title: "Keurig Dr Pepper",
+		Text:  "Keurig Dr Pepper",
+	}}, security.Links)
+	assertEqual(t, 2, security.Row)
+	assertEqual(t, 2, security.Col)
+	assertEqual(t, false, security.Header)
+
+	date := table.HeaderCells[0][0]
+	assertEqual(t, "Date", date.Text)
+	assertEqual(t, true, date.Header)
+	assertEqual(t, 2, date.RowSpan)
+	assertEqual(t, "2", date.Attr("rowspan"))
+	assertEqual(t, date, table.HeaderCells[1][0])
+
+	added := table.HeaderCells[0][1]
+	assertEqual(t, 2, added.ColSpan)
+	assertEqual(t, added, table.HeaderCells[0][2])
+}
+
+func TestCellsWithNestedTables(t *testing.T) {
+	p, err := NewFromString(fixtureNested)
+	assertNoError(t, err)
+	outer := p.Tables[0]
+	assertEqual(t, []*Table{p.Tables[1]}, outer.Cells[0][1].Tables)
+	assertEqual(t, []*Table{p.Tables[2]}, outer.Cells[1][1].Tables)
+	assertEqual(t, 0, len(outer.Cells[1][0].Tables))
+}
+
+func TestFooterCells(t *testing.T) {
+	p, err := NewFromString(fixtureSections)
+	assertNoError(t, err)
+	assertEqual(t, "Total", p.Tables[0].FooterCells[0][0].String())
+}
@@ -0,0 +1,104 @@
+package htmltable
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Column is the logical column of the table, as declared by <col>
+// and <colgroup> elements
+type Column struct {
+	// Class and Style hold attributes of the <col> element,
+	// or of <colgroup> without <col> elements
+	Class string
+	Style string
+
+	// Attrs holds all attributes of <colgroup> and <col> elements,
+	// where the latter take precedence
+	Attrs map[string]string
+
+	// Group is the text of <th scope="colgroup"> heading the column group
+	Group string
+
+	// index of <colgroup> in the table, starting from one,
+	// or zero for columns without one
+	group int
+}
+
+// addColumns declares columns of the <colgroup> element
+func (t *tableBuilder) addColumns(n *html.Node) {
+	t.colgroups++
+	attrs := map[string]string{}
+	for _, a := range n.Attr {
+		attrs[a.Key] = a.Val
+	}
+	hasCols := false
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.Data != "col" {
+			continue
+		}
+		hasCols = true
+		col := map[string]string{}
+		for k, v := range attrs {
+			col[k] = v
+		}
+		for _, a := range c.Attr {
+			col[a.Key] = a.Val
+		}
+		t.declare(col, span(c))
+	}
+	if !hasCols {
+		t.declare(attrs, span(n))
+	}
+}
+
+func (t *tableBuilder) declare(attrs map[string]string, span int) {
+	for i := 0; i < span; i++ {
+		t.columns = append(t.columns, Column{
+			Class: attrs["class"],
+			Style: attrs["style"],
+			Attrs: attrs,
+			group: t.colgroups,
+		})
+	}
+}
+
+// span parses the span attribute of <col> or <colgroup> the same way as colspan
+func span(n *html.Node) int {
+	v, ok := parseNonNegativeInteger(attr(n, "span"))
+	if !ok || v == 0 {
+		return 1
+	}
+	if v > maxColSpan {
+		return maxColSpan
+	}
+	return v
+}
+
+// labelGroups makes <th scope="colgroup"> in header rows cover every
+// empty slot of its column group and names the group of columns after it
+func (g *grid) labelGroups(columns []Column, headerRows int) {
+	for y := 0; y < headerRows; y++ {
+		for x, c := range g.slots[y] {
+			if c == nil || c.Col != x || x >= len(columns) || columns[x].group == 0 {
+				continue
+			}
+			if strings.ToLower(c.Attrs["scope"]) != "colgroup" {
+				continue
+			}
+			group := columns[x].group
+			for x := range columns {
+				if columns[x].group != group {
+					continue
+				}
+				if g.slots[y][x] == nil {
+					g.slots[y][x] = c
+				}
+				if g.slots[y][x] == c {
+					columns[x].Group = c.Text
+				}
+			}
+		}
+	}
+}
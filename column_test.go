@@ -0,0 +1,41 @@
+package htmltable
+
+import "testing"
+
+const fixtureColgroups = `<table>
+	<colgroup class="name"></colgroup>
+	<colgroup span="2" style="background: grey"></colgroup>
+	<colgroup><col class="eur"><col span="2" class="usd"></colgroup>
+	<thead>
+		<tr><td></td><th scope="colgroup" colspan="2">Sales</th><th scope="colgroup">Prices</th></tr>
+		<tr><th>Name</th><th>Q1</th><th>Q2</th><th>EUR</th><th>USD</th><th>USD, net</th></tr>
+	</thead>
+	<tr><td>Apple</td><td>1</td><td>2</td><td>3</td><td>4</td></tr>
+</table>`
+
+func TestColgroups(t *testing.T) {
+	p, err := NewFromString(fixtureColgroups)
+	assertNoError(t, err)
+	table := p.Tables[0]
+	assertEqual(t, []string{"Name", "Sales Q1", "Sales Q2", "Prices EUR", "Prices USD", "Prices USD, net"}, table.Header)
+	assertEqual(t, []string{"Apple", "1", "2", "3", "4", ""}, table.Rows[0])
+	assertEqual(t, 6, len(table.Columns))
+	assertEqual(t, "name", table.Columns[0].Class)
+	assertEqual(t, "", table.Columns[0].Group)
+	assertEqual(t, "background: grey", table.Columns[2].Style)
+	assertEqual(t, "Sales", table.Columns[2].Group)
+	assertEqual(t, "usd", table.Columns[5].Class)
+	assertEqual(t, "Prices", table.Columns[5].Group)
+}
+
+func TestColumnsWidenGrid(t *testing.T) {
+	p, err := NewFromString(`<table>
+		<col span="3">
+		<tr><th>a</th><th>b</th></tr>
+		<tr><td>1</td></tr>
+	</table>`)
+	assertNoError(t, err)
+	assertEqual(t, []string{"a", "b", ""}, p.Tables[0].Header)
+	assertEqual(t, [][]string{{"1", "", ""}}, p.Tables[0].Rows)
+	assertEqual(t, map[string]string{"span": "3"}, p.Tables[0].Columns[2].Attrs)
+}
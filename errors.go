@@ -0,0 +1,104 @@
+package htmltable
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrTableNotFound is matched by errors.Is for every error,
+// that means no table has the requested columns
+var ErrTableNotFound = errors.New("table not found")
+
+// MissingColumnsError is returned when no table has all requested columns
+type MissingColumnsError struct {
+	// Columns holds all requested column names
+	Columns []string
+
+	// Closest is the table having the most of requested columns,
+	// or nil if there are no tables to look into
+	Closest *Table
+
+	// Missing holds column names, that the closest table does not have
+	Missing []string
+}
+
+func (e *MissingColumnsError) Error() string {
+	msg := fmt.Sprintf("cannot find table with columns: %s",
+		strings.Join(e.Columns, ", "))
+	if e.Closest == nil {
+		return msg
+	}
+	return fmt.Sprintf("%s; closest %s misses: %s", msg,
+		e.Closest, strings.Join(e.Missing, ", "))
+}
+
+// Is makes errors.Is(err, ErrTableNotFound) true
+func (e *MissingColumnsError) Is(target error) bool {
+	return target == ErrTableNotFound
+}
+
+// AmbiguousTableError is returned when more than one table
+// matches requested columns equally well
+type AmbiguousTableError struct {
+	// Columns holds all requested column names
+	Columns []string
+
+	// Candidates holds all tables matching the columns in document order
+	Candidates []*Table
+}
+
+func (e *AmbiguousTableError) Error() string {
+	// and do a best-effort error message, that is cleaner than pandas.read_html
+	msg := fmt.Sprintf("more than one table matches columns `%s`",
+		strings.Join(e.Columns, ", "))
+	if len(e.Candidates) < 2 {
+		return msg
+	}
+	return fmt.Sprintf("%s: %s and %s", msg, e.Candidates[0], e.Candidates[1])
+}
+
+// how much of the response body is kept in ResponseError
+const bodySnippetSize = 512
+
+// ResponseError is returned for HTTP responses with status other than 2xx,
+// unless the status is accepted with WithAcceptedStatus option
+type ResponseError struct {
+	// URL of the request
+	URL string
+
+	// StatusCode and Status are the same as in http.Response
+	StatusCode int
+	Status     string
+
+	// Header holds response headers, like Retry-After
+	Header http.Header
+
+	// Body holds the beginning of the response body
+	Body string
+}
+
+func newResponseError(resp *http.Response) *ResponseError {
+	e := &ResponseError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+	}
+	if e.Status == "" {
+		e.Status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		e.URL = resp.Request.URL.String()
+	}
+	if resp.Body != nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, bodySnippetSize))
+		e.Body = string(body)
+	}
+	return e
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.URL, e.Status)
+}
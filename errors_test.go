@@ -0,0 +1,98 @@
+package htmltable
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMissingColumnsError(t *testing.T) {
+	p, err := NewFromString(fixture)
+	assertNoError(t, err)
+	_, err = p.FindWithColumns("c", "d", "e")
+	assertEqualError(t, err, "cannot find table with columns: c, d, e; "+
+		"closest Table[b, c, d] (2 rows) misses: e")
+	assertEqual(t, true, errors.Is(err, ErrTableNotFound))
+
+	var missing *MissingColumnsError
+	assertEqual(t, true, errors.As(err, &missing))
+	assertEqual(t, p.Tables[1], missing.Closest)
+	assertEqual(t, []string{"e"}, missing.Missing)
+
+	err = p.Each("z", func(a string) error {
+		return nil
+	})
+	assertEqual(t, true, errors.Is(err, ErrTableNotFound))
+}
+
+func TestMissingColumnsErrorWithoutTables(t *testing.T) {
+	_, err := NewSliceFromString[nice]("<p>no tables</p>")
+	assertEqualError(t, err, "cannot find table with columns: c, d")
+	assertEqual(t, true, errors.Is(err, ErrTableNotFound))
+
+	var missing *MissingColumnsError
+	assertEqual(t, true, errors.As(err, &missing))
+	assertEqual(t, (*Table)(nil), missing.Closest)
+	assertEqual(t, []string{"c", "d"}, missing.Missing)
+}
+
+func TestAmbiguousTableError(t *testing.T) {
+	p, err := NewFromString(fixture)
+	assertNoError(t, err)
+	_, err = p.FindWithColumns("b")
+	assertEqualError(t, err, "more than one table matches columns `b`: "+
+		"Table[a, b] (2 rows) and Table[b, c, d] (2 rows)")
+	assertEqual(t, false, errors.Is(err, ErrTableNotFound))
+
+	var ambiguous *AmbiguousTableError
+	assertEqual(t, true, errors.As(err, &ambiguous))
+	assertEqual(t, p.Tables, ambiguous.Candidates)
+
+	type onlyB struct {
+		B string `header:"b"`
+	}
+	_, err = NewSliceFromPage[onlyB](p)
+	assertEqual(t, true, errors.As(err, &ambiguous))
+
+	err = &AmbiguousTableError{Columns: []string{"b"}}
+	assertEqualError(t, err, "more than one table matches columns `b`")
+}
+
+func TestResponseError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(fixture))
+	}))
+	defer server.Close()
+
+	_, err := NewFromURL(server.URL + "/list")
+	assertEqualError(t, err, server.URL+"/list: 503 Service Unavailable")
+	var re *ResponseError
+	assertEqual(t, true, errors.As(err, &re))
+	assertEqual(t, http.StatusServiceUnavailable, re.StatusCode)
+	assertEqual(t, "120", re.Header.Get("Retry-After"))
+	assertEqual(t, fixture[:32], re.Body[:32])
+
+	_, err = NewSliceFromURL[nice](server.URL)
+	assertEqual(t, true, errors.As(err, &re))
+
+	out, err := NewSliceFromURL[nice](server.URL, WithAcceptedStatus(http.StatusServiceUnavailable))
+	assertNoError(t, err)
+	assertEqual(t, 2, len(out))
+}
+
+func TestResponseErrorBodyIsTruncated(t *testing.T) {
+	_, err := NewFromResponse(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(strings.NewReader(strings.Repeat("x", 1024))),
+		Request:    &http.Request{},
+	})
+	assertEqualError(t, err, ": 404 Not Found")
+	var re *ResponseError
+	assertEqual(t, true, errors.As(err, &re))
+	assertEqual(t, bodySnippetSize, len(re.Body))
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/nfx/go-htmltable"
 )
 
-func ExampleNewSliceFromUrl() {
+func ExampleNewSliceFromURL() {
 	type Ticker struct {
 		Symbol   string `header:"Symbol"`
 		Security string `header:"Security"`
@@ -86,7 +86,7 @@ func ExampleNewFromURL() {
 	fmt.Println(err)
 
 	// Output:
-	// cannot find table with columns: invalid, column, names
+	// cannot find table with columns: invalid, column, names; closest Table[Symbol, Security, SEC filings, GICSSector, GICS Sub-Industry, Headquarters Location, Date first added, CIK, Founded] (503 rows) misses: invalid, column, names
 }
 
 func ExampleLogger() {
@@ -0,0 +1,169 @@
+package htmltable
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// fetch performs GET request with the client, headers and cookies from
+// options. Responses with 429 and 5xx statuses are retried, if configured
+// with WithRetries, and the last one is returned.
+func fetch(ctx context.Context, url string, o *options) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range o.requestHeader {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	for _, cookie := range o.cookies {
+		req.AddCookie(cookie)
+	}
+	client := o.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	backoff := o.backoff
+	for attempt := 0; ; attempt++ {
+		if o.limiter != nil {
+			err = o.limiter.Wait(ctx, req.URL.Host)
+			if err != nil {
+				return nil, err
+			}
+		}
+		resp, err := client.Do(req)
+		if err != nil || attempt >= o.retries || !retryable(resp.StatusCode) {
+			return resp, err
+		}
+		maxDelay := o.maxRetryDelay
+		if maxDelay == 0 {
+			maxDelay = defaultMaxRetryDelay
+		}
+		delay, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if ok && delay > maxDelay {
+			// server asks to come back much later, so ResponseError
+			// tells the caller instead of blocking for that long
+			return resp, nil
+		}
+		if !ok {
+			delay = backoff
+		}
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+		backoff = nextBackoff(backoff, maxDelay)
+		// body is drained, so that the connection is reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		err = sleep(ctx, delay)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// the longest delay before retries, unless set with WithMaxRetryDelay
+const defaultMaxRetryDelay = time.Minute
+
+// nextBackoff doubles the delay up to maxDelay, so that it never overflows
+func nextBackoff(backoff, maxDelay time.Duration) time.Duration {
+	if backoff < maxDelay/2 {
+		return backoff * 2
+	}
+	return maxDelay
+}
+
+// retryable is true for statuses of throttled or failing servers
+func retryable(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// retryAfter parses Retry-After header, that is either
+// the number of seconds or the date
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(header)
+	if err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(header)
+	if err != nil {
+		return 0, false
+	}
+	if date.Before(now) {
+		return 0, true
+	}
+	return date.Sub(now), true
+}
+
+// sleep waits for the duration or till the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// RateLimiter keeps the interval between requests to the same host.
+// It is safe for concurrent use and is meant to be shared by all
+// URL constructors of the program with WithRateLimiter option.
+type RateLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+// NewRateLimiter returns limiter with the interval between requests to the same host
+func NewRateLimiter(interval time.Duration) *RateLimiter {
+	return &RateLimiter{
+		interval: interval,
+		next:     map[string]time.Time{},
+	}
+}
+
+// Wait blocks till the request to the host is allowed or the context is done
+func (l *RateLimiter) Wait(ctx context.Context, host string) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	l.next[host] = at.Add(l.interval)
+	l.mu.Unlock()
+	return sleep(ctx, at.Sub(now))
+}
+
+// limitedBody fails reading more than max bytes,
+// so that truncated pages are not parsed
+type limitedBody struct {
+	r   io.Reader
+	max int64
+	n   int64
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.n > l.max {
+		return n, fmt.Errorf("response body is larger than %d bytes", l.max)
+	}
+	return n, err
+}
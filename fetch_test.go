@@ -0,0 +1,172 @@
+package htmltable
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewFromURLContextSendsHeadersAndCookies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertEqual(t, "htmltable-test", r.Header.Get("User-Agent"))
+		cookie, err := r.Cookie("session")
+		assertNoError(t, err)
+		assertEqual(t, "abc", cookie.Value)
+		_, _ = w.Write([]byte(fixture))
+	}))
+	defer server.Close()
+	client := &http.Client{Timeout: time.Second}
+	p, err := NewFromURLContext(context.Background(), server.URL,
+		WithHTTPClient(client),
+		WithHeader("User-Agent", "htmltable-test"),
+		WithCookie(&http.Cookie{Name: "session", Value: "abc"}))
+	assertNoError(t, err)
+	assertEqual(t, 2, p.Len())
+}
+
+func TestNewSliceFromURLContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(fixture))
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewSliceFromURLContext[nice](ctx, server.URL)
+	assertEqual(t, true, errors.Is(err, context.Canceled))
+}
+
+func TestWithMaxBodySize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(fixture + strings.Repeat(" ", 1024)))
+	}))
+	defer server.Close()
+	_, err := NewFromURL(server.URL, WithMaxBodySize(1024))
+	assertEqualError(t, err, "response body is larger than 1024 bytes")
+
+	out, err := NewSliceFromURL[nice](server.URL, WithMaxBodySize(4096))
+	assertNoError(t, err)
+	assertEqual(t, 2, len(out))
+}
+
+func TestRetriesThrottledRequests(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch attempts {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			_, _ = w.Write([]byte(fixture))
+		}
+	}))
+	defer server.Close()
+	out, err := NewSliceFromURL[nice](server.URL, WithRetries(2, time.Millisecond))
+	assertNoError(t, err)
+	assertEqual(t, 2, len(out))
+	assertEqual(t, 3, attempts)
+}
+
+func TestRetriesGiveUp(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	_, err := NewFromURL(server.URL, WithRetries(2, time.Millisecond))
+	var re *ResponseError
+	assertEqual(t, true, errors.As(err, &re))
+	assertEqual(t, 3, attempts)
+
+	attempts = 0
+	_, err = NewFromURL(server.URL)
+	assertError(t, err)
+	assertEqual(t, 1, attempts)
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	for header, expected := range map[string]time.Duration{
+		"120":                           2 * time.Minute,
+		"Sat, 01 Jan 2022 00:00:30 GMT": 30 * time.Second,
+		"Fri, 31 Dec 2021 23:00:00 GMT": 0,
+	} {
+		delay, ok := retryAfter(header, now)
+		assertEqual(t, true, ok)
+		assertEqual(t, expected, delay)
+	}
+	for _, header := range []string{"", "soon", "-1"} {
+		_, ok := retryAfter(header, now)
+		assertEqual(t, false, ok)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(fixture))
+	}))
+	defer server.Close()
+	limiter := NewRateLimiter(20 * time.Millisecond)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := NewFromURL(server.URL, WithRateLimiter(limiter))
+		assertNoError(t, err)
+	}
+	assertEqual(t, true, time.Since(start) >= 40*time.Millisecond)
+
+	err := limiter.Wait(context.Background(), "example.com")
+	assertNoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = limiter.Wait(ctx, "example.com")
+	assertEqual(t, context.Canceled, err)
+}
+
+func TestRetryAfterOverMaxDelayGivesUp(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "86400")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	start := time.Now()
+	_, err := NewSliceFromURL[nice](server.URL, WithRetries(3, time.Millisecond))
+	var re *ResponseError
+	assertEqual(t, true, errors.As(err, &re))
+	assertEqual(t, http.StatusTooManyRequests, re.StatusCode)
+	assertEqual(t, 1, attempts)
+	assertEqual(t, true, time.Since(start) < time.Second)
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	start := time.Now()
+	_, err := NewFromURL(server.URL, WithRetries(2, time.Hour), WithMaxRetryDelay(time.Millisecond))
+	assertError(t, err)
+	assertEqual(t, 3, attempts)
+	assertEqual(t, true, time.Since(start) < time.Second)
+}
+
+func TestNextBackoffStaysPositive(t *testing.T) {
+	backoff := time.Second
+	for i := 0; i < 100; i++ {
+		backoff = nextBackoff(backoff, time.Minute)
+		assertEqual(t, true, backoff > 0)
+		assertEqual(t, true, backoff <= time.Minute)
+	}
+	assertEqual(t, time.Minute, backoff)
+	assertEqual(t, 4*time.Second, nextBackoff(2*time.Second, time.Minute))
+}
@@ -0,0 +1,231 @@
+package htmltable
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Footnote is the reference from the cell to a footnote or a citation
+type Footnote struct {
+	// Marker is the text of the reference, like "[4]" or "[a]"
+	Marker string
+
+	// Href is the target of the reference, like "#cite_note-4"
+	Href string
+
+	// Text of the footnote, that the reference points to, if found
+	Text string
+}
+
+// trailingMarkers matches citation markers at the end of the text,
+// like "[4]", "[a]", "[note 1]" or "[citation needed]"
+var trailingMarkers = regexp.MustCompile(
+	`(\s*\[(\d{1,4}|[a-zA-Z]{1,2}|note \d+|citation needed)\])+$`)
+
+// stripMarkers removes citation markers from the end of the text
+func stripMarkers(text string) string {
+	return trailingMarkers.ReplaceAllString(text, "")
+}
+
+// isFootnote is true for citation elements, like <sup class="reference">
+func isFootnote(n *html.Node) bool {
+	return n.Data == "sup" && includesWord(attr(n, "class"), "reference")
+}
+
+// isMarker is true for <sup> elements without the reference class, that
+// hold only citation markers, like <sup>[4]</sup> or <sup>[citation needed]</sup>
+func (p *Page) isMarker(n *html.Node) bool {
+	if n.Data != "sup" {
+		return false
+	}
+	text := p.supText(n)
+	return text != "" && stripMarkers(text) == ""
+}
+
+// supText returns compact text within the <sup> element, which itself
+// is skipped, when markers are stripped
+func (p *Page) supText(n *html.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		p.compactText(c, &sb)
+	}
+	return sb.String()
+}
+
+// plainMarker matches text of <sup> elements without the reference class,
+// that still look like footnote markers, like [1] or [a]. Markers without
+// brackets, like a or *, count only when linking to the note, because
+// they can't be told apart from ordinals, like 1<sup>st</sup>.
+var plainMarker = regexp.MustCompile(`^\[[0-9a-zA-Z*]{1,3}\]$`)
+
+// linkedMarker matches markers without brackets
+var linkedMarker = regexp.MustCompile(`^[0-9a-zA-Z*]{1,3}$`)
+
+// noteDefinition matches the line of footnote text within the table,
+// like "[a] text", "a. text", "1) text" or "^ a text"
+var noteDefinition = regexp.MustCompile(
+	`^(?:\[([0-9a-zA-Z*]{1,3})\]|([0-9a-zA-Z*]{1,3})[.):]|\^\s*([0-9a-zA-Z*]{1,3}))\s+(.+)$`)
+
+// markerKey returns the marker without brackets, so that "[a]" is "a"
+func markerKey(marker string) string {
+	return strings.Trim(strings.TrimSpace(marker), "[]^ ")
+}
+
+// footnotes returns references within the node
+func (p *Page) footnotes(n *html.Node) []Footnote {
+	footnotes := []Footnote{}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type != html.ElementNode || n.Data == "table" {
+			return
+		}
+		if isFootnote(n) {
+			a := findElement(n, func(n *html.Node) bool {
+				return n.Data == "a"
+			})
+			footnotes = append(footnotes, Footnote{
+				Marker: p.supText(n),
+				Href:   attr(a, "href"),
+			})
+			return
+		}
+		if n.Data == "sup" {
+			a := findElement(n, func(n *html.Node) bool {
+				return n.Data == "a" && strings.HasPrefix(attr(n, "href"), "#")
+			})
+			marker := p.supText(n)
+			if plainMarker.MatchString(marker) || (a != nil && linkedMarker.MatchString(marker)) {
+				footnotes = append(footnotes, Footnote{
+					Marker: marker,
+					Href:   attr(a, "href"),
+				})
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c)
+	}
+	return footnotes
+}
+
+// resolveFootnotes finds text for references in all cells, either by the
+// id of the element they link to, like an item in the list of references,
+// or by the marker in the notes at the bottom of the table
+func (p *Page) resolveFootnotes() {
+	ids := map[string]*html.Node{}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if id := attr(n, "id"); id != "" {
+			if _, ok := ids[id]; !ok {
+				ids[id] = n
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(p.root)
+	for _, table := range p.Tables {
+		notes := p.tableNotes(table)
+		seen := map[*Cell]bool{}
+		for _, rows := range [][][]*Cell{table.HeaderCells, table.Cells, table.FooterCells, {table.groupCells}} {
+			for _, row := range rows {
+				for _, c := range row {
+					if c == nil || seen[c] {
+						continue
+					}
+					seen[c] = true
+					for i, fn := range c.Footnotes {
+						target, ok := ids[strings.TrimPrefix(fn.Href, "#")]
+						if ok && strings.HasPrefix(fn.Href, "#") {
+							c.Footnotes[i].Text = p.noteText(target)
+							continue
+						}
+						c.Footnotes[i].Text = notes[markerKey(fn.Marker)]
+					}
+				}
+			}
+		}
+	}
+}
+
+// noteText returns text of the footnote without backlinks to references
+func (p *Page) noteText(n *html.Node) string {
+	if text := findElement(n, func(n *html.Node) bool {
+		return includesWord(attr(n, "class"), "reference-text")
+	}); text != nil {
+		n = text
+	}
+	var sb strings.Builder
+	tw := &textWriter{
+		sb: &sb,
+		skip: func(n *html.Node) bool {
+			return includesWord(attr(n, "class"), "mw-cite-backlink")
+		},
+	}
+	tw.walk(n)
+	return sb.String()
+}
+
+// tableNotes returns footnotes defined in the footer of the table or in
+// rows of one cell spanning the whole table, with one note per line
+func (p *Page) tableNotes(table *Table) map[string]string {
+	notes := map[string]string{}
+	rows := append(table.Cells[:len(table.Cells):len(table.Cells)], table.FooterCells...)
+	for _, c := range table.groupCells {
+		rows = append(rows, []*Cell{c})
+	}
+	for _, row := range rows {
+		if len(row) == 0 || row[0] == nil {
+			continue
+		}
+		fullWidth := true
+		for _, c := range row {
+			if c != row[0] {
+				fullWidth = false
+			}
+		}
+		if !fullWidth {
+			continue
+		}
+		var sb strings.Builder
+		tw := &textWriter{sb: &sb, block: true, skip: p.skip}
+		tw.walk(row[0].node)
+		for _, line := range strings.Split(sb.String(), "\n") {
+			m := noteDefinition.FindStringSubmatch(strings.TrimSpace(line))
+			if m == nil {
+				continue
+			}
+			notes[m[1]+m[2]+m[3]] = m[4]
+		}
+	}
+	return notes
+}
+
+// Footnotes returns text of footnotes by their markers, like "c" for [c],
+// for every reference found in the table, that could be resolved.
+func (table *Table) Footnotes() map[string]string {
+	footnotes := map[string]string{}
+	for _, rows := range [][][]*Cell{table.HeaderCells, table.Cells, table.FooterCells, {table.groupCells}} {
+		for _, row := range rows {
+			for _, c := range row {
+				if c == nil {
+					continue
+				}
+				for _, fn := range c.Footnotes {
+					if fn.Text == "" {
+						continue
+					}
+					footnotes[markerKey(fn.Marker)] = fn.Text
+				}
+			}
+		}
+	}
+	return footnotes
+}
@@ -0,0 +1,133 @@
+package htmltable
+
+import (
+	"testing"
+)
+
+func TestWithoutFootnotes(t *testing.T) {
+	p, err := NewFromString(am4info, WithoutFootnotes())
+	assertNoError(t, err)
+	table := p.Tables[0]
+	assertEqual(t, "PCIesupport", table.Header[2])
+	assertEqual(t, "CPU support Zen 2", table.Header[14])
+	assertEqual(t, []string{"B550", "June 2020", "PCIe 3.0 ×10"}, table.Rows[6][:3])
+	assertEqual(t, "Varies", table.Rows[3][15])
+	assertEqual(t, "~15 W", table.Rows[7][10])
+	assertEqual(t, []Footnote{
+		{Marker: "[d]", Href: "#cite_note-zen3-27"},
+		{Marker: "[18]", Href: "#cite_note-Tom's_Hardware_Zen3_Update-28"},
+	}, table.Cells[3][15].Footnotes)
+}
+
+func TestFootnotesAreKeptByDefault(t *testing.T) {
+	p, err := NewFromString(fixtureColspans)
+	assertNoError(t, err)
+	cell := p.Tables[0].Cells[0][5]
+	assertEqual(t, "Market capitalization change.[4]", cell.Text)
+	assertEqual(t, []Footnote{{Marker: "[4]", Href: "#cite_note-sp20220603-4"}}, cell.Footnotes)
+}
+
+func TestWithoutFootnotesKeepsBracketedValues(t *testing.T) {
+	p, err := NewFromString(`<table>
+		<tr><th>Expression</th><th>Size</th></tr>
+		<tr><td>arr[i]</td><td>Size [10]</td></tr>
+		<tr><td>x<sup>[1]</sup></td><td>2<sup>nd</sup></td></tr>
+	</table>`, WithoutFootnotes())
+	assertNoError(t, err)
+	assertEqual(t, [][]string{
+		{"arr[i]", "Size [10]"},
+		{"x", "2nd"},
+	}, p.Tables[0].Rows)
+	assertEqual(t, []Footnote{{Marker: "[1]"}}, p.Tables[0].Cells[1][0].Footnotes)
+}
+
+func TestStripMarkers(t *testing.T) {
+	for in, out := range map[string]string{
+		"Varies[c]":                        "Varies",
+		"Market capitalization change.[4]": "Market capitalization change.",
+		"~15 W[23][24] [f]":                "~15 W",
+		"Claim[citation needed]":           "Claim",
+		"Population[note 3]":               "Population",
+		"Array[index]":                     "Array[index]",
+		"[a] first":                        "[a] first",
+	} {
+		assertEqual(t, out, stripMarkers(in))
+	}
+}
+
+func TestNewSliceWithoutFootnotes(t *testing.T) {
+	type chipset struct {
+		Model      string `header:"Model"`
+		SupportZen string `header:"CPU support Zen"`
+	}
+	out, err := NewSliceFromString[chipset](am4info, WithoutFootnotes())
+	assertNoError(t, err)
+	assertEqual(t, chipset{"B550", "Varies"}, out[6])
+}
+
+const fixtureFootnoteTexts = `<body>
+<table>
+	<tr><th>Model</th><th>Zen 2</th></tr>
+	<tr><td>A320</td><td>Varies<sup class="reference"><a href="#cite_note-zen2-25">[c]</a></sup></td></tr>
+	<tr><td>X570</td><td>Yes<sup>[x]</sup></td></tr>
+	<tr><td colspan="2"><ol><li>[x] Only with BIOS update</li></ol></td></tr>
+</table>
+<ol class="references">
+	<li id="cite_note-zen2-25"><span class="mw-cite-backlink"><b><a href="#cite_ref-zen2_25-0">^</a></b></span> <span class="reference-text">Zen 2 support depends on the board</span></li>
+</ol>
+</body>`
+
+func TestFootnoteTexts(t *testing.T) {
+	p, err := NewFromString(fixtureFootnoteTexts)
+	assertNoError(t, err)
+	table := p.Tables[0]
+	assertEqual(t, []Footnote{{
+		Marker: "[c]",
+		Href:   "#cite_note-zen2-25",
+		Text:   "Zen 2 support depends on the board",
+	}}, table.Cells[0][1].Footnotes)
+	assertEqual(t, []Footnote{{
+		Marker: "[x]",
+		Text:   "Only with BIOS update",
+	}}, table.Cells[1][1].Footnotes)
+	assertEqual(t, map[string]string{
+		"c": "Zen 2 support depends on the board",
+		"x": "Only with BIOS update",
+	}, table.Footnotes())
+}
+
+func TestNoteTextWithoutBacklinks(t *testing.T) {
+	p, err := NewFromString(`<table>
+		<tr><th>a</th></tr>
+		<tr><td>1<sup class="reference"><a href="#n1">[1]</a></sup></td></tr>
+	</table>
+	<p id="n1"><span class="mw-cite-backlink">^</span> Plain  note</p>`)
+	assertNoError(t, err)
+	assertEqual(t, "Plain note", p.Tables[0].Cells[0][0].Footnotes[0].Text)
+}
+
+func TestNewSliceBindsFootnotes(t *testing.T) {
+	type chipset struct {
+		Model string `header:"Model"`
+		Zen2  string `header:"Zen 2"`
+		Note  string `header:"Zen 2,footnote"`
+	}
+	out, err := NewSliceFromString[chipset](fixtureFootnoteTexts, WithoutFootnotes())
+	assertNoError(t, err)
+	assertEqual(t, chipset{"A320", "Varies", "Zen 2 support depends on the board"}, out[0])
+	assertEqual(t, chipset{"X570", "Yes", "Only with BIOS update"}, out[1])
+}
+
+func TestSupWithoutBracketsIsNotFootnote(t *testing.T) {
+	p, err := NewFromString(`<table>
+		<tr><th>Place</th><th>Area</th></tr>
+		<tr><td>1<sup>st</sup></td><td>10 km<sup>2</sup></td></tr>
+		<tr><td>2<sup><a href="#note-b">b</a></sup></td><td>5</td></tr>
+		<tr><td colspan="2"><ol><li id="note-b">Shared</li></ol></td></tr>
+	</table>`)
+	assertNoError(t, err)
+	cells := p.Tables[0].Cells
+	assertEqual(t, []Footnote{}, cells[0][0].Footnotes)
+	assertEqual(t, []Footnote{}, cells[0][1].Footnotes)
+	assertEqual(t, []Footnote{{Marker: "b", Href: "#note-b", Text: "Shared"}}, cells[1][0].Footnotes)
+}
@@ -3,3 +3,5 @@ module github.com/nfx/go-htmltable
 go 1.18
 
 require golang.org/x/net v0.26.0
+
+require golang.org/x/text v0.16.0
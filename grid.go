@@ -0,0 +1,209 @@
+package htmltable
+
+import (
+	"golang.org/x/net/html"
+)
+
+// limits from the HTML specification
+const (
+	maxColSpan = 1000
+	maxRowSpan = 65534
+)
+
+// grid is the result of forming a table, as described in the
+// https://html.spec.whatwg.org/multipage/tables.html#forming-a-table
+// except that rowspans end with the last row of their row group.
+//
+// Cells with colspan or rowspan are referenced from every slot they cover.
+// When cells overlap, which is a table model error, the slot is kept
+// by the cell, that covered it first.
+type grid struct {
+	// slots addressable by y and x
+	slots [][]*Cell
+	// rows, that started every grid row
+	rows []*row
+	// sections of every grid row
+	sections []section
+	width    int
+}
+
+// formTable places rows into the grid. Rows of the same group must be adjacent.
+func formTable(rows []row) *grid {
+	g := &grid{}
+	ycurrent := 0
+	for i := 0; i < len(rows); {
+		// the algorithm for processing row groups
+		group, s := rows[i].group, rows[i].section
+		end := i
+		for end < len(rows) && rows[end].group == group {
+			end++
+		}
+		downward := []*Cell{}
+		for ; i < end; i++ {
+			// the algorithm for processing rows
+			r := &rows[i]
+			if len(g.slots) == ycurrent {
+				g.grow(ycurrent+1, s)
+			}
+			g.rows[ycurrent] = r
+			g.growDownward(downward, ycurrent)
+			xcurrent := 0
+			for _, c := range r.cells {
+				for xcurrent < g.width && g.at(xcurrent, ycurrent) != nil {
+					xcurrent++
+				}
+				if xcurrent == g.width {
+					g.width++
+				}
+				rowSpan := c.RowSpan
+				if rowSpan == 0 {
+					// cell grows downward till the end of row group
+					downward = append(downward, c)
+					rowSpan = 1
+				}
+				if rowSpan > end-i {
+					// rows, that no cell starts, are not emitted anyway, so
+					// spans are clipped at the end of the row group instead
+					// of growing the grid for thousands of empty rows
+					rowSpan = end - i
+				}
+				if g.width < xcurrent+c.ColSpan {
+					g.width = xcurrent + c.ColSpan
+				}
+				if len(g.slots) < ycurrent+rowSpan {
+					g.grow(ycurrent+rowSpan, s)
+				}
+				c.Col, c.Row = xcurrent, ycurrent
+				for y := ycurrent; y < ycurrent+rowSpan; y++ {
+					g.cover(c, y)
+				}
+				xcurrent += c.ColSpan
+			}
+			ycurrent++
+		}
+		// ending a row group
+		for ycurrent < len(g.slots) {
+			g.growDownward(downward, ycurrent)
+			ycurrent++
+		}
+	}
+	for y := range g.slots {
+		g.fill(y)
+	}
+	return g
+}
+
+// at returns the cell covering the slot, if any
+func (g *grid) at(x, y int) *Cell {
+	if x >= len(g.slots[y]) {
+		return nil
+	}
+	return g.slots[y][x]
+}
+
+// grow adds rows to the grid till it has the given height
+func (g *grid) grow(height int, s section) {
+	for len(g.slots) < height {
+		g.slots = append(g.slots, nil)
+		g.rows = append(g.rows, nil)
+		g.sections = append(g.sections, s)
+	}
+}
+
+// fill makes the row as wide as the grid
+func (g *grid) fill(y int) {
+	for len(g.slots[y]) < g.width {
+		g.slots[y] = append(g.slots[y], nil)
+	}
+}
+
+// cover assigns the cell to the slots it covers in the given row
+func (g *grid) cover(c *Cell, y int) {
+	g.fill(y)
+	for x := c.Col; x < c.Col+c.ColSpan; x++ {
+		if g.slots[y][x] != nil {
+			// table model error: cells overlap
+			continue
+		}
+		g.slots[y][x] = c
+	}
+}
+
+// growDownward extends cells with rowspan of zero to the given row
+func (g *grid) growDownward(downward []*Cell, y int) {
+	for _, c := range downward {
+		g.cover(c, y)
+	}
+}
+
+// colSpan parses the colspan attribute: invalid and zero values
+// fall back to one and large values are clamped
+func colSpan(n *html.Node) int {
+	v, ok := parseNonNegativeInteger(attr(n, "colspan"))
+	if !ok || v == 0 {
+		return 1
+	}
+	if v > maxColSpan {
+		return maxColSpan
+	}
+	return v
+}
+
+// rowSpan parses the rowspan attribute: invalid values fall back to one,
+// large values are clamped and zero is kept to span till the end of group
+func rowSpan(n *html.Node) int {
+	v, ok := parseNonNegativeInteger(attr(n, "rowspan"))
+	if !ok {
+		return 1
+	}
+	if v > maxRowSpan {
+		return maxRowSpan
+	}
+	return v
+}
+
+// parseNonNegativeInteger follows the rules for parsing non-negative
+// integers: leading whitespace and trailing garbage are ignored,
+// so that " 2px" is 2, but "px2" is invalid.
+func parseNonNegativeInteger(s string) (int, bool) {
+	i := 0
+	for i < len(s) && isASCIIWhitespace(s[i]) {
+		i++
+	}
+	negative := false
+	if i < len(s) && (s[i] == '-' || s[i] == '+') {
+		negative = s[i] == '-'
+		i++
+	}
+	if i == len(s) || s[i] < '0' || s[i] > '9' {
+		return 0, false
+	}
+	v := 0
+	for ; i < len(s) && s[i] >= '0' && s[i] <= '9'; i++ {
+		if v <= maxRowSpan {
+			// anything above is clamped anyway
+			v = v*10 + int(s[i]-'0')
+		}
+	}
+	if negative && v != 0 {
+		return 0, false
+	}
+	return v, true
+}
+
+func isASCIIWhitespace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\f' || b == '\r'
+}
+
+// attr returns the value of the attribute or an empty string
+func attr(n *html.Node, key string) string {
+	if n == nil {
+		return ""
+	}
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,212 @@
+package htmltable
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// formGrid returns values of every slot of the first table in the input
+func formGrid(t *testing.T, in string) [][]string {
+	root, err := html.Parse(strings.NewReader(in))
+	assertNoError(t, err)
+	var find func(n *html.Node) *html.Node
+	find = func(n *html.Node) *html.Node {
+		if n.Type == html.ElementNode && n.Data == "table" {
+			return n
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if found := find(c); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	b := &tableBuilder{section: sectionBody}
+	p := &Page{stack: []*tableBuilder{b}}
+	for c := find(root).FirstChild; c != nil; c = c.NextSibling {
+		p.parse(c)
+	}
+	g := b.form()
+	out := [][]string{}
+	for _, slots := range g.slots {
+		out = append(out, values(slots))
+	}
+	return out
+}
+
+func TestFormingTable(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		in   string
+		grid [][]string
+	}{
+		{
+			name: "plain",
+			in:   `<table><tr><td>a</td><td>b</td></tr><tr><td>c</td><td>d</td></tr></table>`,
+			grid: [][]string{{"a", "b"}, {"c", "d"}},
+		},
+		{
+			name: "rowspan shifts cells of the next row",
+			in: `<table>
+				<tr><td rowspan="2">a</td><td>b</td><td>c</td></tr>
+				<tr><td>d</td><td>e</td></tr>
+			</table>`,
+			grid: [][]string{{"a", "b", "c"}, {"a", "d", "e"}},
+		},
+		{
+			name: "rowspan in the middle",
+			in: `<table>
+				<tr><td>a</td><td rowspan="3">b</td><td>c</td></tr>
+				<tr><td>d</td><td>e</td></tr>
+				<tr><td>f</td><td>g</td></tr>
+			</table>`,
+			grid: [][]string{{"a", "b", "c"}, {"d", "b", "e"}, {"f", "b", "g"}},
+		},
+		{
+			name: "colspan and rowspan block",
+			in: `<table>
+				<tr><td colspan="2" rowspan="2">a</td><td>b</td></tr>
+				<tr><td>c</td></tr>
+				<tr><td>d</td><td>e</td><td>f</td></tr>
+			</table>`,
+			grid: [][]string{{"a", "a", "b"}, {"a", "a", "c"}, {"d", "e", "f"}},
+		},
+		{
+			name: "rowspans in header are resolved",
+			in: `<table>
+				<thead>
+					<tr><th rowspan="2">a</th><th colspan="2">b</th></tr>
+					<tr><th>c</th><th>d</th></tr>
+				</thead>
+				<tbody><tr><td>1</td><td>2</td><td>3</td></tr></tbody>
+			</table>`,
+			grid: [][]string{{"a", "b", "b"}, {"a", "c", "d"}, {"1", "2", "3"}},
+		},
+		{
+			name: "short rows are padded",
+			in: `<table>
+				<tr><td>a</td></tr>
+				<tr><td>b</td><td>c</td><td>d</td></tr>
+			</table>`,
+			grid: [][]string{{"a", "", ""}, {"b", "c", "d"}},
+		},
+		{
+			name: "rowspan zero grows till the end of row group",
+			in: `<table>
+				<tbody>
+					<tr><td rowspan="0">a</td><td>b</td></tr>
+					<tr><td>c</td></tr>
+					<tr><td>d</td></tr>
+				</tbody>
+				<tbody>
+					<tr><td>e</td><td>f</td></tr>
+				</tbody>
+			</table>`,
+			grid: [][]string{{"a", "b"}, {"a", "c"}, {"a", "d"}, {"e", "f"}},
+		},
+		{
+			name: "rowspan does not cross row groups",
+			in: `<table>
+				<tbody>
+					<tr><td rowspan="3">a</td><td>b</td></tr>
+				</tbody>
+				<tbody>
+					<tr><td>c</td><td>d</td></tr>
+				</tbody>
+			</table>`,
+			grid: [][]string{{"a", "b"}, {"c", "d"}},
+		},
+		{
+			name: "tfoot is placed at the end",
+			in: `<table>
+				<tfoot><tr><td>total</td></tr></tfoot>
+				<tbody><tr><td>a</td></tr></tbody>
+			</table>`,
+			grid: [][]string{{"a"}, {"total"}},
+		},
+		{
+			name: "thead is placed at the beginning",
+			in: `<table>
+				<tbody><tr><td>a</td></tr></tbody>
+				<thead><tr><th>h</th></tr></thead>
+			</table>`,
+			grid: [][]string{{"h"}, {"a"}},
+		},
+		{
+			name: "empty row is covered by rowspan",
+			in: `<table>
+				<tr><td rowspan="3">a</td><td>b</td></tr>
+				<tr></tr>
+				<tr><td>c</td></tr>
+			</table>`,
+			grid: [][]string{{"a", "b"}, {"a", ""}, {"a", "c"}},
+		},
+		{
+			name: "overlapping cells keep the first one",
+			in: `<table>
+				<tr><td>a</td><td rowspan="2">b</td></tr>
+				<tr><td colspan="2">c</td></tr>
+			</table>`,
+			grid: [][]string{{"a", "b"}, {"c", "b"}},
+		},
+		{
+			name: "zero, negative and garbage spans fall back to one",
+			in: `<table>
+				<tr><td colspan="0">a</td><td colspan="x">b</td><td rowspan="-1">c</td><td rowspan="@#$">d</td></tr>
+				<tr><td>e</td><td>f</td><td>g</td><td>h</td></tr>
+			</table>`,
+			grid: [][]string{{"a", "b", "c", "d"}, {"e", "f", "g", "h"}},
+		},
+		{
+			name: "spans are parsed as leading digits",
+			in: `<table>
+				<tr><td colspan=" 2px">a</td><td rowspan="+2">b</td></tr>
+				<tr><td>c</td><td>d</td></tr>
+			</table>`,
+			grid: [][]string{{"a", "a", "b"}, {"c", "d", "b"}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assertEqual(t, tt.grid, formGrid(t, tt.in))
+		})
+	}
+}
+
+func TestFormingTableClampsSpans(t *testing.T) {
+	wide := formGrid(t, `<table><tr><td colspan="5000">a</td></tr></table>`)
+	assertEqual(t, maxColSpan, len(wide[0]))
+	assertEqual(t, "a", wide[0][maxColSpan-1])
+
+	tall := formGrid(t, `<table><tr><td rowspan="100000">a</td></tr><tr><td>b</td></tr></table>`)
+	assertEqual(t, [][]string{{"a", ""}, {"a", "b"}}, tall)
+	p, err := NewFromString(`<table><tr><td rowspan="100000">a</td></tr></table>`)
+	assertNoError(t, err)
+	assertEqual(t, maxRowSpan, p.Tables[0].HeaderCells[0][0].RowSpan)
+}
+
+func TestHugeSpansStayBounded(t *testing.T) {
+	huge := strings.Repeat(`<table><tr><td rowspan="65534" colspan="1000">x</td></tr></table>`, 3)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	p, err := NewFromString(huge)
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	assertNoError(t, err)
+	assertEqual(t, 3, p.Len())
+	assertEqual(t, true, elapsed < time.Second)
+	assertEqual(t, true, after.TotalAlloc-before.TotalAlloc < 64<<20)
+}
+
+func TestPhantomRowsAreNotEmitted(t *testing.T) {
+	p, err := NewFromString(`<table>
+		<tr><th>a</th><th>b</th></tr>
+		<tr><td rowspan="5">1</td><td>2</td></tr>
+	</table>`)
+	assertNoError(t, err)
+	assertEqual(t, [][]string{{"1", "2"}}, p.Tables[0].Rows)
+}
@@ -0,0 +1,114 @@
+package htmltable
+
+import "strings"
+
+// assignHeaders sets Cell.Headers of every cell in the grid, either from
+// ids in its headers attribute, or from header cells above and to the left
+// of it. Columns without header text, which cells refer to header cells
+// explicitly, are named by those header cells.
+func (g *grid) assignHeaders(header []string, headerRows int) {
+	ids := map[string]*Cell{}
+	for _, slots := range g.slots {
+		for _, c := range slots {
+			if c == nil || c.Attrs["id"] == "" {
+				continue
+			}
+			if _, ok := ids[c.Attrs["id"]]; !ok {
+				ids[c.Attrs["id"]] = c
+			}
+		}
+	}
+	named := map[int]bool{}
+	for y, slots := range g.slots {
+		for x, c := range slots {
+			if c == nil || c.Row != y || c.Col != x {
+				// cells spanning more slots are handled once
+				continue
+			}
+			refs := strings.Fields(c.Attrs["headers"])
+			if len(refs) == 0 {
+				c.Headers = texts(g.implicitHeaders(c, headerRows))
+				continue
+			}
+			cells := []*Cell{}
+			for _, id := range refs {
+				h, ok := ids[id]
+				if !ok || h == c {
+					continue
+				}
+				cells = append(cells, h)
+			}
+			c.Headers = texts(cells)
+			if y < headerRows || named[x] || x >= len(header) || header[x] != "" {
+				continue
+			}
+			names := []string{}
+			for _, h := range cells {
+				if h.RowHeader || h.Text == "" || isGroupLabel(h, headerRows) {
+					// labels of rows and row groups would only fit some rows
+					continue
+				}
+				names = append(names, h.Text)
+			}
+			if len(names) == 0 {
+				continue
+			}
+			header[x] = strings.Join(names, " ")
+			named[x] = true
+		}
+	}
+}
+
+// implicitHeaders returns header cells of the column above the cell, from
+// header rows and the nearest <th scope="col"> or <th scope="colgroup">
+// within the body, followed by row headers to the left of the cell.
+func (g *grid) implicitHeaders(c *Cell, headerRows int) []*Cell {
+	cells := []*Cell{}
+	seen := map[*Cell]bool{c: true}
+	add := func(h *Cell) {
+		if h == nil || seen[h] || h.Text == "" {
+			return
+		}
+		seen[h] = true
+		cells = append(cells, h)
+	}
+	var group *Cell
+	for y := 0; y < c.Row; y++ {
+		h := g.at(c.Col, y)
+		if h == nil || !h.Header || h.RowHeader {
+			continue
+		}
+		if y < headerRows {
+			add(h)
+			continue
+		}
+		scope := strings.ToLower(h.Attrs["scope"])
+		if scope == "col" || scope == "colgroup" {
+			group = h
+		}
+	}
+	add(group)
+	for x := 0; x < c.Col; x++ {
+		h := g.at(x, c.Row)
+		if h != nil && h.RowHeader {
+			add(h)
+		}
+	}
+	return cells
+}
+
+// isGroupLabel is true for <th scope="colgroup"> or <th scope="rowgroup">
+// below the header
+func isGroupLabel(c *Cell, headerRows int) bool {
+	scope := strings.ToLower(c.Attrs["scope"])
+	return c.Row >= headerRows && (scope == "colgroup" || scope == "rowgroup")
+}
+
+// texts returns text of every cell
+func texts(cells []*Cell) []string {
+	texts := make([]string, len(cells))
+	for i, c := range cells {
+		texts[i] = c.Text
+	}
+	return texts
+}
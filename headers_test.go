@@ -0,0 +1,64 @@
+package htmltable
+
+import "testing"
+
+const fixtureHeadersAttribute = `<table>
+	<thead>
+		<tr><td></td><th id="y20" scope="col">2020</th><th id="y21" scope="col">2021</th></tr>
+	</thead>
+	<tbody>
+		<tr><th id="rev" scope="colgroup" colspan="3">Revenue</th></tr>
+		<tr><th id="n" scope="row">North</th><td headers="rev y20 n">10</td><td headers="rev y21 n">12</td></tr>
+		<tr><th scope="row">South</th><td>7</td><td>8</td></tr>
+	</tbody>
+	<tbody>
+		<tr><th id="cost" scope="colgroup" colspan="3">Costs</th></tr>
+		<tr><th id="n2" scope="row">North</th><td headers="cost y20 n2">4</td><td headers="cost y21 n2">5</td></tr>
+	</tbody>
+</table>`
+
+func TestHeadersAttribute(t *testing.T) {
+	p, err := NewFromString(fixtureHeadersAttribute)
+	assertNoError(t, err)
+	table := p.Tables[0]
+	assertEqual(t, []string{"", "2020", "2021"}, table.Header)
+	assertEqual(t, []string{"Revenue", "2020", "North"}, table.Cells[0][1].Headers)
+	assertEqual(t, []string{"Revenue", "2021", "North"}, table.Cells[0][2].Headers)
+	assertEqual(t, []string{"Costs", "2020", "North"}, table.Cells[2][1].Headers)
+
+	type year struct {
+		Region string `header:",rowheader"`
+		Y2020  int    `header:"2020"`
+	}
+	out, err := NewSliceFromPage[year](p)
+	assertNoError(t, err)
+	assertEqual(t, []year{{"North", 10}, {"South", 7}, {"North", 4}}, out)
+}
+
+func TestHeadersAttributeNamesColumnWithoutHeader(t *testing.T) {
+	p, err := NewFromString(`<table>
+		<thead><tr><th>Region</th><td></td></tr></thead>
+		<tbody>
+			<tr><th id="n" scope="rowgroup" colspan="2">North</th></tr>
+			<tr><td>Oslo</td><td headers="n amount">10</td></tr>
+		</tbody>
+		<tfoot><tr><th id="amount">Amount</th><td>10</td></tr></tfoot>
+	</table>`)
+	assertNoError(t, err)
+	assertEqual(t, []string{"Region", "Amount"}, p.Tables[0].Header)
+	assertEqual(t, []string{"North", "Amount"}, p.Tables[0].Cells[0][1].Headers)
+}
+
+func TestImplicitHeaders(t *testing.T) {
+	p, err := NewFromString(fixtureHeadersAttribute)
+	assertNoError(t, err)
+	table := p.Tables[0]
+	assertEqual(t, []string{"2020", "Revenue", "South"}, table.Cells[1][1].Headers)
+	assertEqual(t, []string{"Revenue"}, table.Cells[1][0].Headers)
+	assertEqual(t, []string{"North", "South", "North"}, table.RowLabels)
+
+	p, err = NewFromString(fixtureRowHeaders)
+	assertNoError(t, err)
+	assertEqual(t, []string{"Population", "Germany"}, p.Tables[0].Cells[1][2].Headers)
+	assertEqual(t, []string{"Capital"}, p.Tables[0].Cells[2][1].Headers)
+}
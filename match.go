@@ -0,0 +1,173 @@
+package htmltable
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MatchStrategy controls how column names are compared to table headers
+type MatchStrategy int
+
+const (
+	// MatchNormalized compares names ignoring case, whitespace, punctuation,
+	// trailing citation markers, like [4], and accents. This is the default.
+	MatchNormalized MatchStrategy = iota
+
+	// MatchExact compares names as they are
+	MatchExact
+
+	// MatchFuzzy compares normalized names by their edit distance, so
+	// that "Release dates" still matches "Release date". See WithMatchThreshold.
+	MatchFuzzy
+)
+
+// default similarity for MatchFuzzy, where 1 is the same name
+const defaultMatchThreshold = 0.8
+
+// matcher scores column names against table headers
+type matcher struct {
+	strategy  MatchStrategy
+	threshold float64
+}
+
+// score is how well table headers match column names. Tables with
+// higher similarity rank first, followed by those with more exact names.
+type score struct {
+	similarity float64
+	exact      int
+}
+
+func (s score) better(other score) bool {
+	if s.similarity != other.similarity {
+		return s.similarity > other.similarity
+	}
+	return s.exact > other.exact
+}
+
+// similarity of the column name to the header from 0 to 1,
+// where 0 means no match
+func (m matcher) similarity(col, header string) float64 {
+	if col == header {
+		return 1
+	}
+	if m.strategy == MatchExact {
+		return 0
+	}
+	a, b := normalize(col), normalize(header)
+	if a == b {
+		return 1
+	}
+	if m.strategy != MatchFuzzy || a == "" || b == "" {
+		return 0
+	}
+	threshold := m.threshold
+	if threshold == 0 {
+		threshold = defaultMatchThreshold
+	}
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	similarity := 1 - float64(levenshtein(ra, rb))/float64(longest)
+	if similarity < threshold {
+		return 0
+	}
+	return similarity
+}
+
+// offset returns position of the header best matching the column, or -1
+func (m matcher) offset(header []string, col string) int {
+	best, offset := 0.0, -1
+	for idx, h := range header {
+		if h == col {
+			return idx
+		}
+		similarity := m.similarity(col, h)
+		if similarity > best {
+			best, offset = similarity, idx
+		}
+	}
+	return offset
+}
+
+// score returns how well the header matches all columns
+// and false if any of the columns has no match
+func (m matcher) score(header []string, columns []string) (score, bool) {
+	var s score
+	for _, col := range columns {
+		offset := m.offset(header, col)
+		if offset < 0 {
+			return s, false
+		}
+		s.similarity += m.similarity(col, header[offset])
+		if col == header[offset] {
+			s.exact++
+		}
+	}
+	return s, true
+}
+
+// normalize folds case, accents, compatibility forms, citation markers,
+// punctuation and whitespace, so that "Release Date[4]" becomes "release date"
+func normalize(name string) string {
+	name = stripMarkers(name)
+	folded, _, err := transform.String(folding, name)
+	if err == nil {
+		name = folded
+	}
+	var sb strings.Builder
+	space := false
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(significant, r) {
+			// whitespace and punctuation separate words
+			space = sb.Len() > 0
+			continue
+		}
+		if space {
+			sb.WriteByte(' ')
+			space = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// folding decomposes letters, like "é" or "ﬁ", drops combining accents
+// and folds case, so that "Straße" is the same as "strasse"
+var folding = transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), cases.Fold(), norm.NFC)
+
+// symbols, that tell names apart, like "Zen" and "Zen+" or "C" and "C#"
+const significant = "+#%$€£"
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if prev[j]+1 < curr[j] {
+				curr[j] = prev[j] + 1
+			}
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
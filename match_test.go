@@ -0,0 +1,90 @@
+package htmltable
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	for in, out := range map[string]string{
+		"Release Date":          "release date",
+		"Release\u00a0date[4]":  "release date",
+		" Population, 2020 ":    "population 2020",
+		"Société":               "societe",
+		"Socie\u0301te\u0301":   "societe",
+		"Zen+":                  "zen+",
+		"Straße":                "strasse",
+		"Erdoğan":               "erdogan",
+		"Őrség":                 "orseg",
+		"ﬁle ＳＩＺＥ":              "file size",
+		"CPU support[14] Zen 2": "cpu support 14 zen 2",
+	} {
+		assertEqual(t, out, normalize(in))
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	assertEqual(t, 0, levenshtein([]rune("date"), []rune("date")))
+	assertEqual(t, 1, levenshtein([]rune("date"), []rune("dates")))
+	assertEqual(t, 3, levenshtein([]rune("kitten"), []rune("sitting")))
+	assertEqual(t, 4, levenshtein([]rune(""), []rune("date")))
+}
+
+const fixtureFuzzyHeaders = `<body>
+<table>
+	<tr><th>Title</th><th>Release Date[4]</th></tr>
+	<tr><td>Go 1.0</td><td>2012</td></tr>
+</table>
+<table>
+	<tr><th>Title</th><th>Release dates</th><th>Notes</th></tr>
+	<tr><td>Go 1.18</td><td>2022</td><td>generics</td></tr>
+</table>
+</body>`
+
+func TestFindWithColumnsNormalized(t *testing.T) {
+	p, err := NewFromString(fixtureFuzzyHeaders)
+	assertNoError(t, err)
+	table, err := p.FindWithColumns("title", "release\u00a0date")
+	assertNoError(t, err)
+	assertEqual(t, "Go 1.0", table.Rows[0][0])
+
+	p, err = NewFromString(fixtureFuzzyHeaders, WithMatchStrategy(MatchExact))
+	assertNoError(t, err)
+	_, err = p.FindWithColumns("title", "release date")
+	assertEqualError(t, err, "cannot find table with columns: title, release date; "+
+		"closest Table[Title, Release Date[4]] (1 rows) misses: title, release date")
+}
+
+func TestFindWithColumnsRanked(t *testing.T) {
+	p, err := NewFromString(fixtureFuzzyHeaders, WithMatchStrategy(MatchFuzzy))
+	assertNoError(t, err)
+	table, err := p.FindWithColumns("Title", "Release dates")
+	assertNoError(t, err)
+	assertEqual(t, "Go 1.18", table.Rows[0][0])
+
+	table, err = p.FindWithColumns("Title", "Release Date")
+	assertNoError(t, err)
+	assertEqual(t, "Go 1.0", table.Rows[0][0])
+
+	tables, err := p.FindAllWithColumns("Title", "Release date")
+	assertNoError(t, err)
+	assertEqual(t, 2, len(tables))
+
+	_, err = p.FindWithColumns("Title")
+	assertError(t, err)
+
+	p, err = NewFromString(fixtureFuzzyHeaders,
+		WithMatchStrategy(MatchFuzzy), WithMatchThreshold(0.95))
+	assertNoError(t, err)
+	_, err = p.FindWithColumns("Notes", "Release date")
+	assertEqualError(t, err, "cannot find table with columns: Notes, Release date; "+
+		"closest Table[Title, Release Date[4]] (1 rows) misses: Notes")
+}
+
+func TestEachUsesMatcher(t *testing.T) {
+	p, err := NewFromString(fixtureFuzzyHeaders, WithMatchStrategy(MatchFuzzy))
+	assertNoError(t, err)
+	err = p.Each2("notes", "release date", func(notes, date string) error {
+		assertEqual(t, "generics", notes)
+		assertEqual(t, "2022", date)
+		return nil
+	})
+	assertNoError(t, err)
+}
@@ -0,0 +1,133 @@
+package htmltable
+
+import "strings"
+
+// MergeWithColumns concatenates rows of all tables having given header
+// column names, like parts of one list split into more tables or into
+// side-by-side halves of the same table. Table.Sources of the result tells,
+// which table in Page.Tables every row comes from.
+func (p *Page) MergeWithColumns(columns ...string) (*Table, error) {
+	found, err := p.findAll(columns)
+	if err != nil {
+		return nil, err
+	}
+	return p.merge(found), nil
+}
+
+// merge concatenates rows of tables at given positions in p.Tables,
+// following the column order of the first one
+func (p *Page) merge(found []candidate) *Table {
+	first := p.Tables[found[0].idx]
+	width := period(first.Header)
+	merged := &Table{
+		Header:       first.Header[:width],
+		HeaderLevels: [][]string{},
+		HeaderCells:  [][]*Cell{},
+		Columns:      first.Columns,
+		Rows:         [][]string{},
+		RowLabels:    []string{},
+		Groups:       []string{},
+		Cells:        [][]*Cell{},
+		Sources:      []int{},
+		Footer:       [][]string{},
+		FooterCells:  [][]*Cell{},
+		Children:     []*Table{},
+		Caption:      first.Caption,
+		Heading:      first.Heading,
+		ID:           first.ID,
+		Class:        first.Class,
+		node:         first.node,
+	}
+	for _, level := range first.HeaderLevels {
+		merged.HeaderLevels = append(merged.HeaderLevels, level[:width])
+	}
+	for _, level := range first.HeaderCells {
+		merged.HeaderCells = append(merged.HeaderCells, level[:width])
+	}
+	if len(merged.Columns) > width {
+		merged.Columns = merged.Columns[:width]
+	}
+	m := p.matcher()
+	for _, c := range found {
+		idx, table := c.idx, p.Tables[c.idx]
+		merged.Children = append(merged.Children, table.Children...)
+		merged.groupCells = append(merged.groupCells, table.groupCells...)
+		w := period(table.Header)
+		for start := 0; start < len(table.Header); start += w {
+			header := table.Header[start : start+w]
+			for y, row := range table.Rows {
+				part := remap(m, merged.Header, header, row[start:start+w])
+				if isBlank(part) {
+					// one of the halves is shorter
+					continue
+				}
+				merged.Rows = append(merged.Rows, part)
+				merged.Cells = append(merged.Cells, remap(m, merged.Header, header, table.Cells[y][start:start+w]))
+				merged.RowLabels = append(merged.RowLabels, table.RowLabels[y])
+				merged.Groups = append(merged.Groups, table.Groups[y])
+				merged.Sources = append(merged.Sources, idx)
+			}
+			for y, row := range table.Footer {
+				part := remap(m, merged.Header, header, row[start:start+w])
+				if isBlank(part) {
+					continue
+				}
+				merged.Footer = append(merged.Footer, part)
+				merged.FooterCells = append(merged.FooterCells, remap(m, merged.Header, header, table.FooterCells[y][start:start+w]))
+				merged.footerSources = append(merged.footerSources, idx)
+			}
+		}
+	}
+	return merged
+}
+
+// period returns the width of the header, that is repeated
+// side-by-side, like "Name, Code, Name, Code", or its full width
+func period(header []string) int {
+	for w := 2; w <= len(header)/2; w++ {
+		if len(header)%w != 0 {
+			continue
+		}
+		repeated := true
+		for i := w; i < len(header); i++ {
+			if header[i] != header[i%w] {
+				repeated = false
+				break
+			}
+		}
+		if repeated && strings.Join(header[:w], "") != "" {
+			return w
+		}
+	}
+	return len(header)
+}
+
+// remap orders values by the header of the merged table. Columns missing
+// in the header of values are empty.
+func remap[T any](m matcher, to, from []string, values []T) []T {
+	same := len(to) == len(from)
+	for i := 0; same && i < len(to); i++ {
+		same = to[i] == from[i]
+	}
+	if same {
+		return values
+	}
+	out := make([]T, len(to))
+	for i, header := range to {
+		idx := m.offset(from, header)
+		if idx >= 0 && idx < len(values) {
+			out[i] = values[idx]
+		}
+	}
+	return out
+}
+
+// isBlank is true if all values are empty
+func isBlank(values []string) bool {
+	for _, v := range values {
+		if v != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package htmltable
+
+import "testing"
+
+const fixtureSplitTables = `<body>
+<table>
+	<tr><th>Name</th><th>Code</th></tr>
+	<tr><td>Austria</td><td>AT</td></tr>
+	<tr><td>Belgium</td><td>BE</td></tr>
+</table>
+<table>
+	<tr><th>Code</th><th>Name</th></tr>
+	<tr><td>NL</td><td>Netherlands</td></tr>
+</table>
+<table>
+	<tr><th>Name</th><th>Code</th><th>Name</th><th>Code</th></tr>
+	<tr><td>Poland</td><td>PL</td><td>Spain</td><td>ES</td></tr>
+	<tr><td>Portugal</td><td>PT</td><td></td><td></td></tr>
+</table>
+</body>`
+
+func TestFindAllWithColumns(t *testing.T) {
+	p, err := NewFromString(fixtureSplitTables)
+	assertNoError(t, err)
+	tables, err := p.FindAllWithColumns("Name", "Code")
+	assertNoError(t, err)
+	assertEqual(t, 3, len(tables))
+
+	_, err = p.FindAllWithColumns("Capital")
+	assertEqualError(t, err, "cannot find table with columns: Capital; "+
+		"closest Table[Name, Code] (2 rows) misses: Capital")
+}
+
+func TestMergeWithColumns(t *testing.T) {
+	p, err := NewFromString(fixtureSplitTables)
+	assertNoError(t, err)
+	table, err := p.MergeWithColumns("Name", "Code")
+	assertNoError(t, err)
+	assertEqual(t, []string{"Name", "Code"}, table.Header)
+	assertEqual(t, [][]string{
+		{"Austria", "AT"},
+		{"Belgium", "BE"},
+		{"Netherlands", "NL"},
+		{"Poland", "PL"},
+		{"Portugal", "PT"},
+		{"Spain", "ES"},
+	}, table.Rows)
+	assertEqual(t, []int{0, 0, 1, 2, 2, 2}, table.Sources)
+	assertEqual(t, "NL", table.Cells[2][1].Text)
+}
@@ -0,0 +1,307 @@
+package htmltable
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option customizes parsing of the page and lookup of the tables on it
+type Option func(*options)
+
+type options struct {
+	footer      bool
+	text        TextMode
+	header      HeaderMode
+	groupColumn string
+	noFootnotes bool
+	displayed   bool
+	sortValues  bool
+
+	repeatedHeaders bool
+	merge           bool
+
+	// statuses other than 2xx, that are parsed
+	accepted []int
+
+	// fetching of URLs
+	client        *http.Client
+	requestHeader http.Header
+	cookies       []*http.Cookie
+	maxBodySize   int64
+	retries       int
+	backoff       time.Duration
+	maxRetryDelay time.Duration
+	limiter       *RateLimiter
+
+	// matching of column names
+	match     MatchStrategy
+	threshold float64
+
+	// lookup filters
+	caption string
+	heading string
+	id      string
+	class   string
+	// CSS selector of <table> elements
+	selector string
+}
+
+// WithFooter makes NewSlice* include rows from <tfoot> after the body rows,
+// which are skipped by default, as they usually hold totals.
+func WithFooter() Option {
+	return func(o *options) {
+		o.footer = true
+	}
+}
+
+// HeaderMode controls which rows of the table form its header
+type HeaderMode int
+
+const (
+	// HeaderAlways takes rows from <thead> or leading rows of <th> cells,
+	// and falls back to the first row of the table. This is the default.
+	HeaderAlways HeaderMode = iota
+
+	// HeaderAuto takes rows from <thead> or leading rows of <th> cells.
+	// Tables without them have no header.
+	HeaderAuto
+
+	// HeaderNever treats all rows as data.
+	HeaderNever
+)
+
+// WithHeaderMode sets how the header of tables is detected. Tables without
+// header have columns named by their position: "0", "1", "2" and so on,
+// which could also be bound with `col:"2"` tag. Structs with only such
+// fields match any table, so pages with more tables need WithSelector
+// or WithID to tell which one.
+func WithHeaderMode(mode HeaderMode) Option {
+	return func(o *options) {
+		o.header = mode
+	}
+}
+
+// WithHTTPClient sets the client for URL constructors, like the one
+// with timeout or proxy. http.DefaultClient is used by default.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithHeader adds the header to requests of URL constructors,
+// like User-Agent
+func WithHeader(key, value string) Option {
+	return func(o *options) {
+		if o.requestHeader == nil {
+			o.requestHeader = http.Header{}
+		}
+		o.requestHeader.Add(key, value)
+	}
+}
+
+// WithCookie adds the cookie to requests of URL constructors
+func WithCookie(cookie *http.Cookie) Option {
+	return func(o *options) {
+		o.cookies = append(o.cookies, cookie)
+	}
+}
+
+// WithMaxBodySize makes responses with body larger than the given
+// number of bytes fail instead of being parsed
+func WithMaxBodySize(size int64) Option {
+	return func(o *options) {
+		o.maxBodySize = size
+	}
+}
+
+// WithRetries makes URL constructors retry requests, which got 429 or 5xx
+// response, up to the given number of times. Delay before the first retry is
+// backoff, and it doubles with every next one, unless the response has
+// Retry-After header. See WithMaxRetryDelay.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.retries = retries
+		o.backoff = backoff
+	}
+}
+
+// WithMaxRetryDelay sets the longest delay before retries, which is one
+// minute by default. Backoff stops growing at it, and responses with
+// Retry-After header asking to wait longer are not retried.
+func WithMaxRetryDelay(delay time.Duration) Option {
+	return func(o *options) {
+		o.maxRetryDelay = delay
+	}
+}
+
+// WithRateLimiter makes URL constructors wait for the limiter before
+// every request, including retries
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(o *options) {
+		o.limiter = limiter
+	}
+}
+
+// WithAcceptedStatus makes responses with given HTTP status codes parsed,
+// like 404 pages, that still have tables. Other statuses except 2xx
+// return ResponseError.
+func WithAcceptedStatus(codes ...int) Option {
+	return func(o *options) {
+		o.accepted = append(o.accepted, codes...)
+	}
+}
+
+// accepts is true for 2xx status codes and the accepted ones.
+// Zero status code, that is not known, is accepted too.
+func (o *options) accepts(code int) bool {
+	if code == 0 || (code >= 200 && code < 300) {
+		return true
+	}
+	for _, accepted := range o.accepted {
+		if accepted == code {
+			return true
+		}
+	}
+	return false
+}
+
+// WithMatchStrategy sets how column names are matched to table headers
+// by FindWithColumns, Each* and NewSlice*.
+func WithMatchStrategy(strategy MatchStrategy) Option {
+	return func(o *options) {
+		o.match = strategy
+	}
+}
+
+// WithMatchThreshold sets the minimal similarity of names from 0 to 1
+// for MatchFuzzy strategy, which is 0.8 by default.
+func WithMatchThreshold(threshold float64) Option {
+	return func(o *options) {
+		o.threshold = threshold
+	}
+}
+
+// WithMerge makes NewSlice* concatenate rows of all tables having the columns
+// of the struct, instead of failing when more than one table has them.
+// Bind `header:",source"` to an int field to know the position of the table
+// in Page.Tables, that the row comes from.
+func WithMerge() Option {
+	return func(o *options) {
+		o.merge = true
+	}
+}
+
+// WithRepeatedHeaders keeps rows, that repeat the header within long
+// tables, which are dropped by default.
+func WithRepeatedHeaders() Option {
+	return func(o *options) {
+		o.repeatedHeaders = true
+	}
+}
+
+// WithGroupColumn adds the column with the given name to the end of every
+// table, that holds the label of the row group, see Table.Groups. The column
+// has no cells and is named the same on every header level.
+func WithGroupColumn(name string) Option {
+	return func(o *options) {
+		o.groupColumn = name
+	}
+}
+
+// WithTextMode sets how text of cells, captions and headings is extracted
+// from the markup. Default is TextCompact.
+func WithTextMode(mode TextMode) Option {
+	return func(o *options) {
+		o.text = mode
+	}
+}
+
+// WithoutFootnotes drops footnote and citation markers, like
+// <sup class="reference">[4]</sup> or <sup>[a]</sup>, from the text of cells,
+// headers and captions, when the page is parsed. Brackets outside of <sup>,
+// like arr[i], are kept. References are still available in Cell.Footnotes.
+func WithoutFootnotes() Option {
+	return func(o *options) {
+		o.noFootnotes = true
+	}
+}
+
+// WithDisplayedOnly ignores text of hidden elements, like sort keys in
+// <span style="display:none">, elements with the hidden attribute or
+// with one of the common classes for hidden content, like "sortkey".
+func WithDisplayedOnly() Option {
+	return func(o *options) {
+		o.displayed = true
+	}
+}
+
+// WithSortValues makes NewSlice* prefer the data-sort-value attribute,
+// see Cell.SortValue, over the text, when setting int fields.
+func WithSortValues() Option {
+	return func(o *options) {
+		o.sortValues = true
+	}
+}
+
+// WithCaption limits lookups to tables, which <caption> contains the text
+func WithCaption(text string) Option {
+	return func(o *options) {
+		o.caption = text
+	}
+}
+
+// WithHeading limits lookups to tables, which nearest preceding
+// <h1>-<h6> heading contains the text
+func WithHeading(text string) Option {
+	return func(o *options) {
+		o.heading = text
+	}
+}
+
+// WithID limits lookups to the table with the given id attribute
+func WithID(id string) Option {
+	return func(o *options) {
+		o.id = id
+	}
+}
+
+// WithClass limits lookups to tables having the given class
+func WithClass(class string) Option {
+	return func(o *options) {
+		o.class = class
+	}
+}
+
+// WithSelector limits lookups to tables, which <table> element
+// matches CSS selector, like `table#prices` or `div.results > table`.
+// See Page.FindBySelector for the supported syntax.
+func WithSelector(selector string) Option {
+	return func(o *options) {
+		o.selector = selector
+	}
+}
+
+// matches is true if the table passes all lookup filters
+func (o *options) matches(t *Table) bool {
+	if !strings.Contains(t.Caption, o.caption) {
+		return false
+	}
+	if !strings.Contains(t.Heading, o.heading) {
+		return false
+	}
+	if o.id != "" && t.ID != o.id {
+		return false
+	}
+	if o.class != "" && !t.HasClass(o.class) {
+		return false
+	}
+	return true
+}
+
+func (o *options) apply(opts []Option) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
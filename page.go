@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
+	"net/url"
 	"strings"
 
 	"golang.org/x/net/html"
@@ -19,55 +19,73 @@ var htmlParse = html.Parse
 type Page struct {
 	Tables []*Table
 
-	ctx      context.Context
-	rowSpans []int
-	colSpans []int
-	row      []string
-	rows     [][]string
-	maxCols  int
-
-	// current row
-	colSpan []int
-	rowSpan []int
-	// all
-	cSpans [][]int
-	rSpans [][]int
+	ctx  context.Context
+	opts options
+	root *html.Node
+	// URL of the page and the href of <base> element, if any,
+	// to resolve relative links
+	url  *url.URL
+	base string
+
+	// tables being parsed, innermost last
+	stack []*tableBuilder
+	// text of the last heading seen
+	heading string
 }
 
 // New returns an instance of the page with possibly more than one table
-func New(ctx context.Context, r io.Reader) (*Page, error) {
+func New(ctx context.Context, r io.Reader, opts ...Option) (*Page, error) {
 	p := &Page{ctx: ctx}
+	p.opts.apply(opts)
 	return p, p.init(r)
 }
 
 // NewFromString is same as New(ctx.Context, io.Reader), but from string
-func NewFromString(r string) (*Page, error) {
-	return New(context.Background(), strings.NewReader(r))
+func NewFromString(r string, opts ...Option) (*Page, error) {
+	return New(context.Background(), strings.NewReader(r), opts...)
 }
 
 // NewFromResponse is same as New(ctx.Context, io.Reader), but from http.Response.
 //
 // In case of failure, returns `ResponseError`, that could be further inspected.
-func NewFromResponse(resp *http.Response) (*Page, error) {
-	p, err := New(resp.Request.Context(), resp.Body)
+func NewFromResponse(resp *http.Response, opts ...Option) (*Page, error) {
+	var o options
+	o.apply(opts)
+	if !o.accepts(resp.StatusCode) {
+		return nil, newResponseError(resp)
+	}
+	var body io.Reader = resp.Body
+	if o.maxBodySize > 0 && body != nil {
+		body = &limitedBody{r: body, max: o.maxBodySize}
+	}
+	p, err := New(resp.Request.Context(), body, opts...)
 	if err != nil {
 		return nil, err
 	}
+	p.url = resp.Request.URL
 	return p, nil
 }
 
 // NewFromURL is same as New(ctx.Context, io.Reader), but from URL.
 //
 // In case of failure, returns `ResponseError`, that could be further inspected.
-func NewFromURL(url string) (*Page, error) {
-	resp, err := http.Get(url)
+func NewFromURL(url string, opts ...Option) (*Page, error) {
+	return NewFromURLContext(context.Background(), url, opts...)
+}
+
+// NewFromURLContext is same as NewFromURL, but the request is bound to the
+// context. See WithHTTPClient, WithHeader, WithCookie and WithMaxBodySize.
+func NewFromURLContext(ctx context.Context, url string, opts ...Option) (*Page, error) {
+	var o options
+	o.apply(opts)
+	resp, err := fetch(ctx, url, &o)
 	if err != nil {
 		return nil, err
 	}
 	if resp.Body != nil {
 		defer resp.Body.Close()
 	}
-	return NewFromResponse(resp)
+	return NewFromResponse(resp, opts...)
 }
 
 // Len returns number of tables found on the page
@@ -75,36 +93,128 @@ func (p *Page) Len() int {
 	return len(p.Tables)
 }
 
-// FindWithColumns performs fuzzy matching of tables by given header column names
+// FindBySelector returns tables, which <table> element matches CSS selector,
+// like `table#prices` or `div.results > table`. Supported are type, #id,
+// .class and [attr] selectors with all attribute operators, :first-child,
+// :last-child, :nth-child(n), :nth-of-type(n), descendant and child
+// combinators, and comma-separated lists.
+func (p *Page) FindBySelector(selector string) ([]*Table, error) {
+	sl, err := compileSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	found := []*Table{}
+	for _, table := range p.Tables {
+		if table.node == nil || !sl.Match(table.node) {
+			continue
+		}
+		found = append(found, table)
+	}
+	return found, nil
+}
+
+// FindWithColumns performs fuzzy matching of tables by given header column
+// names and returns the best matching one, see MatchStrategy.
 func (p *Page) FindWithColumns(columns ...string) (*Table, error) {
-	// realistic p won't have this much
-	found := 0xfffffff
+	found, err := p.findAll(columns)
+	if err != nil {
+		return nil, err
+	}
+	best := found[0]
+	for _, c := range found[1:] {
+		if c.score.better(best.score) {
+			best = c
+		}
+	}
+	tied := []int{}
+	for _, c := range found {
+		if !best.score.better(c.score) {
+			tied = append(tied, c.idx)
+		}
+	}
+	if len(tied) > 1 {
+		ambiguous := &AmbiguousTableError{Columns: columns}
+		for _, idx := range tied {
+			ambiguous.Candidates = append(ambiguous.Candidates, p.Tables[idx])
+		}
+		return nil, ambiguous
+	}
+	return p.Tables[best.idx], nil
+}
+
+// FindAllWithColumns returns all tables having given header column names,
+// like parts of one list split into more tables.
+func (p *Page) FindAllWithColumns(columns ...string) ([]*Table, error) {
+	found, err := p.findAll(columns)
+	if err != nil {
+		return nil, err
+	}
+	tables := []*Table{}
+	for _, c := range found {
+		tables = append(tables, p.Tables[c.idx])
+	}
+	return tables, nil
+}
+
+// candidate is the table matching columns
+type candidate struct {
+	// position in p.Tables
+	idx   int
+	score score
+}
+
+// findAll returns tables having given columns in the document order
+func (p *Page) findAll(columns []string) ([]candidate, error) {
+	var selected map[*Table]bool
+	if p.opts.selector != "" {
+		tables, err := p.FindBySelector(p.opts.selector)
+		if err != nil {
+			return nil, err
+		}
+		selected = map[*Table]bool{}
+		for _, table := range tables {
+			selected[table] = true
+		}
+	}
+	m := p.matcher()
+	found := []candidate{}
+	missing := &MissingColumnsError{
+		Columns: columns,
+		Missing: columns,
+	}
 	for idx, table := range p.Tables {
-		matchedColumns := 0
-		for _, col := range columns {
-			for _, header := range table.Header {
-				if col == header {
-					// perform fuzzy matching of table headers
-					matchedColumns++
-				}
-			}
+		if selected != nil && !selected[table] {
+			continue
 		}
-		if matchedColumns != len(columns) {
+		if !p.opts.matches(table) {
 			continue
 		}
-		if found < len(p.Tables) {
-			// and do a best-effort error message, that is cleaner than pandas.read_html
-			return nil, fmt.Errorf("more than one table matches columns `%s`: "+
-				"[%d] %s and [%d] %s", strings.Join(columns, ", "),
-				found, p.Tables[found], idx, p.Tables[idx])
+		score, ok := m.score(table.Header, columns)
+		if ok {
+			found = append(found, candidate{idx, score})
+			continue
+		}
+		absent := []string{}
+		for _, col := range columns {
+			if m.offset(table.Header, col) < 0 {
+				absent = append(absent, col)
+			}
+		}
+		if missing.Closest == nil || len(absent) < len(missing.Missing) {
+			missing.Closest, missing.Missing = table, absent
 		}
-		found = idx
 	}
-	if found > len(p.Tables) {
-		return nil, fmt.Errorf("cannot find table with columns: %s",
-			strings.Join(columns, ", "))
+	if len(found) == 0 {
+		return nil, missing
+	}
+	return found, nil
+}
+
+func (p *Page) matcher() matcher {
+	return matcher{
+		strategy:  p.opts.match,
+		threshold: p.opts.threshold,
 	}
-	return p.Tables[found], nil
 }
 
 // Each row would call func with the value of the table cell from the column
@@ -116,15 +226,12 @@ func (p *Page) Each(a string, f func(a string) error) error {
 	if err != nil {
 		return err
 	}
-	offsets := map[string]int{}
-	for idx, header := range table.Header {
-		offsets[header] = idx
-	}
+	_1 := p.matcher().offset(table.Header, a)
 	for idx, row := range table.Rows {
 		if len(row) < 1 {
 			continue
 		}
-		err = f(row[offsets[a]])
+		err = f(row[_1])
 		if err != nil {
 			return fmt.Errorf("row %d: %w", idx, err)
 		}
@@ -141,11 +248,8 @@ func (p *Page) Each2(a, b string, f func(a, b string) error) error {
 	if err != nil {
 		return err
 	}
-	offsets := map[string]int{}
-	for idx, header := range table.Header {
-		offsets[header] = idx
-	}
-	_1, _2 := offsets[a], offsets[b]
+	m := p.matcher()
+	_1, _2 := m.offset(table.Header, a), m.offset(table.Header, b)
 	for idx, row := range table.Rows {
 		if len(row) < 2 {
 			continue
@@ -167,11 +271,8 @@ func (p *Page) Each3(a, b, c string, f func(a, b, c string) error) error {
 	if err != nil {
 		return err
 	}
-	offsets := map[string]int{}
-	for idx, header := range table.Header {
-		offsets[header] = idx
-	}
-	_1, _2, _3 := offsets[a], offsets[b], offsets[c]
+	m := p.matcher()
+	_1, _2, _3 := m.offset(table.Header, a), m.offset(table.Header, b), m.offset(table.Header, c)
 	for idx, row := range table.Rows {
 		if len(row) < 3 {
 			continue
@@ -189,210 +290,294 @@ func (p *Page) init(r io.Reader) error {
 	if err != nil {
 		return err
 	}
+	p.root = root
 	p.parse(root)
-	p.finishTable()
+	tables := p.Tables[:0]
+	for _, table := range p.Tables {
+		if table == nil {
+			// tables without rows are not emitted
+			continue
+		}
+		tables = append(tables, table)
+	}
+	p.Tables = tables
+	p.resolveFootnotes()
 	return nil
 }
 
+// resolve makes the reference absolute, if the page URL is known
+func (p *Page) resolve(ref string) string {
+	base := p.url
+	if p.base != "" {
+		b, err := url.Parse(p.base)
+		if err == nil && base != nil {
+			base = base.ResolveReference(b)
+		} else if err == nil && b.IsAbs() {
+			base = b
+		}
+	}
+	if base == nil || ref == "" {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
+
+// current returns the innermost table being parsed
+func (p *Page) current() *tableBuilder {
+	if len(p.stack) == 0 {
+		return nil
+	}
+	return p.stack[len(p.stack)-1]
+}
+
 func (p *Page) parse(n *html.Node) {
 	if n == nil {
 		return
 	}
+	t := p.current()
 	switch n.Data {
 	case "td", "th":
-		p.colSpan = append(p.colSpan, p.intAttrOr(n, "colspan", 1))
-		p.rowSpan = append(p.rowSpan, p.intAttrOr(n, "rowspan", 1))
+		if t == nil {
+			break
+		}
 		var sb strings.Builder
 		p.innerText(n, &sb)
-		p.row = append(p.row, sb.String())
+		c := &Cell{
+			Text:    sb.String(),
+			Header:  n.Data == "th",
+			ColSpan: colSpan(n),
+			RowSpan: rowSpan(n),
+			Attrs:   map[string]string{},
+			node:    n,
+		}
+		for _, a := range n.Attr {
+			c.Attrs[a.Key] = a.Val
+		}
+		scope := strings.ToLower(c.Attrs["scope"])
+		c.RowHeader = c.Header && (scope == "row" || scope == "rowgroup")
+		c.HTML = p.innerHTML(n)
+		c.Links = p.links(n)
+		c.Footnotes = p.footnotes(n)
+		c.SortValue = c.findAttr("data-sort-value")
+		t.addCell(c)
+		// nested tables are parsed after the cell text is taken
+		t.cell = c
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			p.parse(c)
+		}
+		t.cell = nil
 		return
 	case "tr":
-		p.finishRow()
-	case "table":
-		p.finishTable()
-	}
-	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		p.parse(c)
-	}
-}
-
-func (p *Page) intAttrOr(n *html.Node, attr string, default_ int) int {
-	for _, a := range n.Attr {
-		if a.Key != attr {
-			continue
+		if t == nil {
+			break
 		}
-		val, err := strconv.Atoi(a.Val)
-		if err != nil {
-			return default_
+		t.startRow()
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			p.parse(c)
 		}
-		return val
-	}
-	return default_
-}
-
-func (p *Page) finishRow() {
-	if len(p.row) == 0 {
+		t.finishRow()
 		return
-	}
-	if len(p.row) > p.maxCols {
-		p.maxCols = len(p.row)
-	}
-	p.rows = append(p.rows, p.row)
-	p.cSpans = append(p.cSpans, p.colSpan)
-	p.rSpans = append(p.rSpans, p.rowSpan)
-	p.row = []string{}
-	p.colSpan = []int{}
-	p.rowSpan = []int{}
-}
-
-type cellSpan struct {
-	BeginX, EndX int
-	BeginY, EndY int
-	Value        string
-}
-
-func (d *cellSpan) Match(x, y int) bool {
-	if d.BeginX > x {
-		return false
-	}
-	if d.EndX <= x {
-		return false
-	}
-	if d.BeginY > y {
-		return false
-	}
-	if d.EndY <= y {
-		return false
-	}
-	return true
-}
-
-type spans []cellSpan
-
-func (s spans) Value(x, y int) (string, bool) {
-	for _, v := range s {
-		if !v.Match(x, y) {
-			continue
+	case "base":
+		if p.base == "" {
+			p.base = attr(n, "href")
 		}
-		return v.Value, true
-	}
-	return "", false
-}
-
-func (p *Page) finishTable() {
-	defer func() {
-		if r := recover(); r != nil {
-			firstRow := []string{}
-			if len(p.rows) > 0 {
-				firstRow = p.rows[0][:]
-			}
-			Logger(p.ctx, "unparsable table", "panic", fmt.Sprintf("%v", r), "firstRow", firstRow)
-		}
-		p.rows = [][]string{}
-		p.colSpans = []int{}
-		p.rowSpans = []int{}
-		p.cSpans = [][]int{}
-		p.rSpans = [][]int{}
-		p.maxCols = 0
-	}()
-	p.finishRow()
-	if len(p.rows) == 0 {
+	case "colgroup":
+		if t == nil {
+			break
+		}
+		t.addColumns(n)
 		return
-	}
-
-	rows := [][]string{}
-	allSpans := spans{}
-	rowSkips := 0
-	gotHeader := false
-
-ROWS:
-	for y := 0; y < len(p.rows); y++ { // rows cols addressable by x
-		currentRow := []string{}
-		skipRow := false
-		k := 0 // next row columns
-		j := 0 // p.rows cols addressable by j
-		for x := 0; x < p.maxCols; x++ {
-			value, ok := allSpans.Value(x, y)
-			if ok {
-				currentRow = append(currentRow, value)
-				continue
-			}
-			if gotHeader && len(p.rows[y]) == 1 && p.rows[y][0] == "" {
-				// this are most likely empty rows or table dividers
-				rowSkips++
-				continue ROWS
-			}
-			if len(p.rSpans[y]) == j {
-				break
-			}
-			rowSpan := p.rSpans[y][j]
-			colSpan := p.cSpans[y][j]
-			value = p.rows[y][j]
-			if gotHeader && (rowSpan > 1 || colSpan > 1) {
-				allSpans = append(allSpans, cellSpan{
-					BeginX: x,
-					EndX:   x + colSpan,
-					BeginY: y,
-					EndY:   y + rowSpan,
-					Value:  value,
-				})
-			}
-			if !gotHeader && colSpan > 1 {
-				skipRow = true
-				// in header: merge, in row - duplicate
-				for q := 0; q < colSpan; q++ {
-					nextValue := fmt.Sprintf("%s %s", value, p.rows[y+1][k])
-					currentRow = append(currentRow, nextValue)
-					k++
-				}
-			} else {
-				currentRow = append(currentRow, value)
-			}
-			j++
+	case "caption":
+		if t == nil {
+			break
 		}
-		if skipRow {
-			rowSkips++
-			y++
+		var sb strings.Builder
+		p.innerText(n, &sb)
+		t.caption = sb.String()
+		return
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		var sb strings.Builder
+		p.innerText(n, &sb)
+		p.heading = sb.String()
+		return
+	case "thead", "tbody", "tfoot":
+		if t == nil {
+			break
 		}
-		gotHeader = true
-		if len(currentRow) > p.maxCols {
-			p.maxCols = len(currentRow)
+		t.startGroup(sections[n.Data])
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			p.parse(c)
 		}
-		rows = append(rows, currentRow)
+		// rows directly in <table> form a group on their own
+		t.startGroup(sectionBody)
+		return
+	case "table":
+		// reserve the slot, so that tables stay in document order,
+		// even though nested tables are finished before outer ones
+		p.stack = append(p.stack, &tableBuilder{
+			index:   len(p.Tables),
+			section: sectionBody,
+			node:    n,
+			heading: p.heading,
+			id:      attr(n, "id"),
+			class:   attr(n, "class"),
+		})
+		p.Tables = append(p.Tables, nil)
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			p.parse(c)
+		}
+		p.finishTable()
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		p.parse(c)
 	}
-	header := rows[0]
-	rows = rows[1:]
-	Logger(p.ctx, "found table", "columns", header, "count", len(rows))
-	p.Tables = append(p.Tables, &Table{
-		Header: header,
-		Rows:   rows,
-	})
 }
 
-func (p *Page) innerText(n *html.Node, sb *strings.Builder) {
-	if n.Type == html.TextNode {
-		sb.WriteString(strings.TrimSpace(n.Data))
-		return
-	}
-	if n.FirstChild == nil {
+// finishTable pops the innermost table from the stack and emits it
+func (p *Page) finishTable() {
+	t := p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
+	table := t.finish(p.ctx, &p.opts)
+	if table == nil {
 		return
 	}
-	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		p.innerText(c, sb)
+	p.Tables[t.index] = table
+	if parent := p.current(); parent != nil {
+		parent.children = append(parent.children, table)
+		if parent.cell != nil {
+			parent.cell.Tables = append(parent.cell.Tables, table)
+		}
 	}
 }
 
 // Table is the low-level representation of raw header and rows.
 //
-// Every cell string value is truncated of its whitespace.
+// Every cell string value is truncated of its whitespace, see TextMode.
 type Table struct {
 	// Header holds names of headers
 	Header []string
 
+	// HeaderLevels holds every header row, from top to bottom, with values
+	// of cells spanning more than one column or row repeated. Header is
+	// the flattened representation of these levels.
+	HeaderLevels [][]string
+
 	// Rows holds slice of string slices
 	Rows [][]string
+
+	// Groups holds the label of the group for every row in Rows, taken
+	// from the preceding row with one cell spanning the whole table, like
+	// "Mobile chipsets", or from <th scope="rowgroup">. Every <tbody>
+	// starts a new group. Rows with group labels are not in Rows.
+	Groups []string
+
+	// Sources holds the position in Page.Tables of the table, that every
+	// row in Rows comes from, if the table is merged from more tables
+	Sources []int
+
+	// RowLabels holds text of the row header for every row in Rows,
+	// or an empty string for rows without one
+	RowLabels []string
+
+	// Footer holds rows from <tfoot>, like totals
+	Footer [][]string
+
+	// HeaderCells, Cells and FooterCells hold cells of HeaderLevels, Rows
+	// and Footer respectively. Cells spanning more than one column or row
+	// are referenced from every position they cover, and positions, that
+	// no cell covers, are nil.
+	HeaderCells [][]*Cell
+	Cells       [][]*Cell
+	FooterCells [][]*Cell
+
+	// Columns holds every logical column of the table, with attributes
+	// of <col> and <colgroup> elements, that declare it
+	Columns []Column
+
+	// Children holds tables nested within cells of this table
+	Children []*Table
+
+	// Caption holds text of the <caption> element
+	Caption string
+
+	// Heading holds text of the nearest <h1>-<h6> element before the table
+	Heading string
+
+	// ID and Class hold attributes of the <table> element
+	ID    string
+	Class string
+
+	// <table> element, so that selectors could be matched
+	node *html.Node
+	// cells of group label rows, that may hold footnotes
+	groupCells []*Cell
+	// same as Sources, but for Footer
+	footerSources []int
+}
+
+// HasClass is true if the class attribute of the table has the class
+func (table *Table) HasClass(class string) bool {
+	return includesWord(table.Class, class)
 }
 
 func (table *Table) String() string {
 	return fmt.Sprintf("Table[%s] (%d rows)", strings.Join(table.Header, ", "), len(table.Rows))
 }
+
+// Transpose returns the table with rows turned into columns, so that the
+// first column of vertical tables, like infoboxes, becomes the header.
+// Footer rows are not transposed.
+func (table *Table) Transpose() *Table {
+	lines := append(table.HeaderCells[:len(table.HeaderCells):len(table.HeaderCells)], table.Cells...)
+	width := 0
+	for _, line := range lines {
+		if len(line) > width {
+			width = len(line)
+		}
+	}
+	cells := make([][]*Cell, width)
+	for x := range cells {
+		cells[x] = make([]*Cell, len(lines))
+		for y, line := range lines {
+			if x < len(line) {
+				cells[x][y] = line[x]
+			}
+		}
+	}
+	transposed := &Table{
+		Header:       []string{},
+		HeaderLevels: [][]string{},
+		HeaderCells:  [][]*Cell{},
+		Rows:         [][]string{},
+		RowLabels:    []string{},
+		Groups:       []string{},
+		Cells:        [][]*Cell{},
+		Footer:       [][]string{},
+		FooterCells:  [][]*Cell{},
+		Columns:      []Column{},
+		Children:     table.Children,
+		Caption:      table.Caption,
+		Heading:      table.Heading,
+		ID:           table.ID,
+		Class:        table.Class,
+		node:         table.node,
+	}
+	if width == 0 {
+		return transposed
+	}
+	transposed.Header = values(cells[0])
+	transposed.HeaderLevels = [][]string{transposed.Header}
+	transposed.HeaderCells = cells[:1]
+	for _, line := range cells[1:] {
+		transposed.Rows = append(transposed.Rows, values(line))
+		transposed.RowLabels = append(transposed.RowLabels, rowLabel(line))
+		transposed.Groups = append(transposed.Groups, "")
+		transposed.Cells = append(transposed.Cells, line)
+	}
+	return transposed
+}
@@ -157,7 +157,8 @@ func TestEachFailsNoCols(t *testing.T) {
 	err = p.Each("x", func(a string) error {
 		return nil
 	})
-	assertEqualError(t, err, "cannot find table with columns: x")
+	assertEqualError(t, err, "cannot find table with columns: x; "+
+		"closest Table[a, b] (2 rows) misses: x")
 }
 
 func TestEach2(t *testing.T) {
@@ -185,7 +186,8 @@ func TestEach2FailsNoCols(t *testing.T) {
 	err = p.Each2("x", "y", func(b, c string) error {
 		return nil
 	})
-	assertEqualError(t, err, "cannot find table with columns: x, y")
+	assertEqualError(t, err, "cannot find table with columns: x, y; "+
+		"closest Table[a, b] (2 rows) misses: x, y")
 }
 
 func TestEach3(t *testing.T) {
@@ -213,7 +215,8 @@ func TestEach3FailsNoCols(t *testing.T) {
 	err = p.Each3("x", "y", "z", func(b, c, d string) error {
 		return nil
 	})
-	assertEqualError(t, err, "cannot find table with columns: x, y, z")
+	assertEqualError(t, err, "cannot find table with columns: x, y, z; "+
+		"closest Table[a, b] (2 rows) misses: x, y, z")
 }
 
 func TestMoreThanOneTableFoundErrors(t *testing.T) {
@@ -236,3 +239,313 @@ func TestNilNodeReturns(t *testing.T) {
 	p := &Page{}
 	p.parse(nil)
 }
+
+const fixtureNested = `<table>
+	<tr><th>Name</th><th>Details</th><th>Status</th></tr>
+	<tr>
+		<td>first</td>
+		<td><table>
+			<tr><th>k</th><th>v</th></tr>
+			<tr><td>a</td><td>1</td></tr>
+		</table></td>
+		<td>up</td>
+	</tr>
+	<tr>
+		<td>second</td>
+		<td><table>
+			<tr><th>k</th><th>v</th></tr>
+			<tr><td>b</td><td>2</td></tr>
+			<tr><td>c</td><td>3</td></tr>
+		</table></td>
+		<td>down</td>
+	</tr>
+</table>`
+
+func TestNestedTables(t *testing.T) {
+	p, err := NewFromString(fixtureNested)
+	assertNoError(t, err)
+	assertEqual(t, 3, p.Len())
+
+	outer := p.Tables[0]
+	assertEqual(t, []string{"Name", "Details", "Status"}, outer.Header)
+	assertEqual(t, [][]string{
+		{"first", "", "up"},
+		{"second", "", "down"},
+	}, outer.Rows)
+	assertEqual(t, []*Table{p.Tables[1], p.Tables[2]}, outer.Children)
+
+	assertEqual(t, [][]string{{"a", "1"}}, p.Tables[1].Rows)
+	assertEqual(t, [][]string{{"b", "2"}, {"c", "3"}}, p.Tables[2].Rows)
+}
+
+const fixtureSections = `<table>
+	<tfoot>
+		<tr><td>Total</td><td>6</td></tr>
+	</tfoot>
+	<thead>
+		<tr><th colspan="2">Fruits</th></tr>
+		<tr><th>Name</th><th>Count</th></tr>
+	</thead>
+	<tbody>
+		<tr><td>apple</td><td>1</td></tr>
+		<tr><td>pear</td><td>2</td></tr>
+	</tbody>
+	<tbody>
+		<tr><td>plum</td><td>3</td></tr>
+	</tbody>
+</table>`
+
+func TestSectionsAreRespected(t *testing.T) {
+	p, err := NewFromString(fixtureSections)
+	assertNoError(t, err)
+	assertEqual(t, 1, p.Len())
+	table := p.Tables[0]
+	assertEqual(t, []string{"Fruits Name", "Fruits Count"}, table.Header)
+	assertEqual(t, [][]string{
+		{"apple", "1"},
+		{"pear", "2"},
+		{"plum", "3"},
+	}, table.Rows)
+	assertEqual(t, [][]string{{"Total", "6"}}, table.Footer)
+}
+
+func TestLeadingHeaderCellRowsFormHeader(t *testing.T) {
+	p, err := NewFromString(`<table>
+		<tr><th rowspan="2">Name</th><th colspan="2">Size</th></tr>
+		<tr><th>W</th><th>H</th></tr>
+		<tr><th>a</th><td>1</td><td>2</td></tr>
+	</table>`)
+	assertNoError(t, err)
+	assertEqual(t, []string{"Name", "Size W", "Size H"}, p.Tables[0].Header)
+	assertEqual(t, [][]string{{"a", "1", "2"}}, p.Tables[0].Rows)
+}
+
+const fixtureThreeLevelHeader = `<table>
+	<tr>
+		<th rowspan="3">Model</th>
+		<th colspan="4">Memory</th>
+		<th rowspan="2" colspan="2">Power</th>
+	</tr>
+	<tr>
+		<th colspan="2">Cache</th>
+		<th rowspan="2">Type</th>
+		<th rowspan="2">Channels</th>
+	</tr>
+	<tr>
+		<th>L2</th><th>L3</th>
+		<th>Idle</th><th>Max</th>
+	</tr>
+	<tr>
+		<td>X1</td><td>1 MB</td><td>8 MB</td><td>DDR4</td><td>2</td><td>5 W</td><td>65 W</td>
+	</tr>
+</table>`
+
+func TestThreeLevelHeader(t *testing.T) {
+	p, err := NewFromString(fixtureThreeLevelHeader)
+	assertNoError(t, err)
+	table := p.Tables[0]
+	assertEqual(t, []string{
+		"Model",
+		"Memory Cache L2",
+		"Memory Cache L3",
+		"Memory Type",
+		"Memory Channels",
+		"Power Idle",
+		"Power Max",
+	}, table.Header)
+	assertEqual(t, [][]string{
+		{"Model", "Memory", "Memory", "Memory", "Memory", "Power", "Power"},
+		{"Model", "Cache", "Cache", "Type", "Channels", "Power", "Power"},
+		{"Model", "L2", "L3", "Type", "Channels", "Idle", "Max"},
+	}, table.HeaderLevels)
+	assertEqual(t, [][]string{
+		{"X1", "1 MB", "8 MB", "DDR4", "2", "5 W", "65 W"},
+	}, table.Rows)
+}
+
+func TestHeaderRowspanPullsNextRowIntoHeader(t *testing.T) {
+	p, err := NewFromString(`<table>
+		<tr><td rowspan="2">Name</td><td colspan="2">Size</td></tr>
+		<tr><td>W</td><td>H</td></tr>
+		<tr><td>a</td><td>1</td><td>2</td></tr>
+	</table>`)
+	assertNoError(t, err)
+	assertEqual(t, []string{"Name", "Size W", "Size H"}, p.Tables[0].Header)
+	assertEqual(t, 2, len(p.Tables[0].HeaderLevels))
+	assertEqual(t, [][]string{{"a", "1", "2"}}, p.Tables[0].Rows)
+}
+
+const fixtureSameColumns = `<body>
+<h2>Desktop</h2>
+<table id="desktop" class="wikitable sortable">
+	<caption>Desktop chipsets</caption>
+	<tr><th>Model</th><th>TDP</th></tr>
+	<tr><td>X570</td><td>15</td></tr>
+</table>
+<h2>Mobile</h2>
+<table id="mobile" class="wikitable">
+	<caption>Mobile chipsets</caption>
+	<tr><th>Model</th><th>TDP</th></tr>
+	<tr><td>FCH</td><td>5</td></tr>
+</table>
+</body>`
+
+func TestTableMetadata(t *testing.T) {
+	p, err := NewFromString(fixtureSameColumns)
+	assertNoError(t, err)
+	table := p.Tables[0]
+	assertEqual(t, "Desktop chipsets", table.Caption)
+	assertEqual(t, "Desktop", table.Heading)
+	assertEqual(t, "desktop", table.ID)
+	assertEqual(t, "wikitable sortable", table.Class)
+	assertEqual(t, true, table.HasClass("sortable"))
+	assertEqual(t, "Mobile", p.Tables[1].Heading)
+}
+
+func TestFindWithColumnsFilteredByMetadata(t *testing.T) {
+	p, err := NewFromString(fixtureSameColumns)
+	assertNoError(t, err)
+	_, err = p.FindWithColumns("Model", "TDP")
+	assertError(t, err)
+
+	for _, opt := range []Option{
+		WithCaption("Mobile"),
+		WithHeading("Mobile"),
+		WithID("mobile"),
+	} {
+		p, err := NewFromString(fixtureSameColumns, opt)
+		assertNoError(t, err)
+		table, err := p.FindWithColumns("Model", "TDP")
+		assertNoError(t, err)
+		assertEqual(t, [][]string{{"FCH", "5"}}, table.Rows)
+	}
+
+	p, err = NewFromString(fixtureSameColumns, WithClass("sortable"))
+	assertNoError(t, err)
+	table, err := p.FindWithColumns("Model", "TDP")
+	assertNoError(t, err)
+	assertEqual(t, "desktop", table.ID)
+}
+
+const fixtureHeaderless = `<table>
+	<tr><td>apple</td><td>1</td></tr>
+	<tr><td>pear</td><td>2</td></tr>
+</table>`
+
+func TestHeaderModes(t *testing.T) {
+	p, err := NewFromString(fixtureHeaderless)
+	assertNoError(t, err)
+	assertEqual(t, []string{"apple", "1"}, p.Tables[0].Header)
+
+	p, err = NewFromString(fixtureHeaderless, WithHeaderMode(HeaderAuto))
+	assertNoError(t, err)
+	assertEqual(t, []string{"0", "1"}, p.Tables[0].Header)
+	assertEqual(t, [][]string{{"apple", "1"}, {"pear", "2"}}, p.Tables[0].Rows)
+	assertEqual(t, [][]string{}, p.Tables[0].HeaderLevels)
+
+	p, err = NewFromString(fixtureSections, WithHeaderMode(HeaderAuto))
+	assertNoError(t, err)
+	assertEqual(t, []string{"Fruits Name", "Fruits Count"}, p.Tables[0].Header)
+
+	p, err = NewFromString(fixtureSections, WithHeaderMode(HeaderNever))
+	assertNoError(t, err)
+	assertEqual(t, []string{"0", "1"}, p.Tables[0].Header)
+	assertEqual(t, []string{"Fruits", "Fruits"}, p.Tables[0].Rows[0])
+}
+
+const fixtureRowHeaders = `<table>
+	<thead><tr><th>Country</th><th>Capital</th><th>Population</th></tr></thead>
+	<tbody>
+		<tr><th scope="row">France</th><td>Paris</td><td>68</td></tr>
+		<tr><th>Germany</th><td>Berlin</td><td>84</td></tr>
+		<tr><td>Italy</td><td>Rome</td><td>59</td></tr>
+	</tbody>
+</table>`
+
+func TestRowHeaders(t *testing.T) {
+	p, err := NewFromString(fixtureRowHeaders)
+	assertNoError(t, err)
+	table := p.Tables[0]
+	assertEqual(t, []string{"France", "Germany", ""}, table.RowLabels)
+	assertEqual(t, true, table.Cells[0][0].RowHeader)
+	assertEqual(t, false, table.Cells[0][1].RowHeader)
+	assertEqual(t, false, table.HeaderCells[0][0].RowHeader)
+	assertEqual(t, []string{"Italy", "Rome", "59"}, table.Rows[2])
+}
+
+const fixtureGroups = `<table>
+	<tr><th>Model</th><th>TDP</th></tr>
+	<tr><td colspan="2">Desktop chipsets</td></tr>
+	<tr><td>X570</td><td>15</td></tr>
+	<tr><td>B550</td><td>5</td></tr>
+	<tr><td colspan="2"></td></tr>
+	<tr><td colspan="2">Mobile chipsets</td></tr>
+	<tr><td>FCH</td><td>5</td></tr>
+	<tbody>
+		<tr><td>A320</td><td>5</td></tr>
+	</tbody>
+	<tbody>
+		<tr><th scope="rowgroup" rowspan="2">Server</th><td>7</td></tr>
+		<tr><td>9</td></tr>
+	</tbody>
+</table>`
+
+func TestGroupRows(t *testing.T) {
+	p, err := NewFromString(fixtureGroups)
+	assertNoError(t, err)
+	table := p.Tables[0]
+	assertEqual(t, []string{"Model", "TDP"}, table.Header)
+	assertEqual(t, [][]string{
+		{"X570", "15"},
+		{"B550", "5"},
+		{"FCH", "5"},
+		{"A320", "5"},
+		{"Server", "7"},
+		{"Server", "9"},
+	}, table.Rows)
+	assertEqual(t, []string{
+		"Desktop chipsets",
+		"Desktop chipsets",
+		"Mobile chipsets",
+		"",
+		"Server",
+		"Server",
+	}, table.Groups)
+}
+
+func TestWithGroupColumn(t *testing.T) {
+	p, err := NewFromString(fixtureGroups, WithGroupColumn("Segment"))
+	assertNoError(t, err)
+	table, err := p.FindWithColumns("Model", "Segment")
+	assertNoError(t, err)
+	assertEqual(t, []string{"X570", "15", "Desktop chipsets"}, table.Rows[0])
+	assertEqual(t, 3, len(table.Cells[0]))
+	assertEqual(t, 3, len(table.Columns))
+	for y, level := range table.HeaderLevels {
+		assertEqual(t, "Segment", level[2])
+		assertEqual(t, 3, len(table.HeaderCells[y]))
+	}
+	assertEqual(t, (*Cell)(nil), table.HeaderCells[0][2])
+}
+
+const fixtureRepeatedHeaders = `<table>
+	<tr><th>Player</th><th>Goals</th></tr>
+	<tr><td>Messi</td><td>672</td></tr>
+	<tr><td>player</td><td> GOALS </td></tr>
+	<tr><td>Pelé</td><td>643</td></tr>
+	<tr><th>Player</th><th>Goals</th></tr>
+	<tr><td>Müller</td><td>365</td></tr>
+	<tfoot><tr><th>Total</th><th>1680</th></tr></tfoot>
+</table>`
+
+func TestRepeatedHeadersAreDropped(t *testing.T) {
+	p, err := NewFromString(fixtureRepeatedHeaders)
+	assertNoError(t, err)
+	assertEqual(t, [][]string{{"Messi", "672"}, {"Pelé", "643"}, {"Müller", "365"}}, p.Tables[0].Rows)
+	assertEqual(t, [][]string{{"Total", "1680"}}, p.Tables[0].Footer)
+
+	p, err = NewFromString(fixtureRepeatedHeaders, WithRepeatedHeaders())
+	assertNoError(t, err)
+	assertEqual(t, 5, len(p.Tables[0].Rows))
+	assertEqual(t, 1, len(p.Tables[0].Footer))
+}
@@ -0,0 +1,345 @@
+package htmltable
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// selectorList is the compiled subset of CSS selectors, that supports
+// type, universal, #id, .class and [attr] selectors, with =, ~=, |=, ^=,
+// $= and *= attribute operators, :first-child, :last-child, :nth-child(n)
+// and :nth-of-type(n) pseudo-classes, descendant and child combinators,
+// as well as comma-separated selector lists.
+type selectorList []complexSelector
+
+// complexSelector holds compound selectors from left to right
+type complexSelector []compoundSelector
+
+type compoundSelector struct {
+	// combinator to the compound selector on the left:
+	// ' ' for descendant and '>' for child
+	combinator byte
+	matchers   []func(n *html.Node) bool
+}
+
+func compileSelector(selector string) (selectorList, error) {
+	sp := &selectorParser{in: selector}
+	list, err := sp.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector `%s`: %w", selector, err)
+	}
+	return list, nil
+}
+
+// Match is true if the element matches any of the selectors
+func (sl selectorList) Match(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	for _, s := range sl {
+		if s.match(n, len(s)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// match checks the node against compound selectors from right to left
+func (s complexSelector) match(n *html.Node, i int) bool {
+	if !s[i].match(n) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	for p := n.Parent; p != nil && p.Type == html.ElementNode; p = p.Parent {
+		if s.match(p, i-1) {
+			return true
+		}
+		if s[i].combinator == '>' {
+			return false
+		}
+	}
+	return false
+}
+
+func (c compoundSelector) match(n *html.Node) bool {
+	for _, m := range c.matchers {
+		if !m(n) {
+			return false
+		}
+	}
+	return true
+}
+
+type selectorParser struct {
+	in  string
+	pos int
+}
+
+func (sp *selectorParser) parse() (selectorList, error) {
+	list := selectorList{}
+	for {
+		s, err := sp.parseComplex()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+		sp.skipWhitespace()
+		if sp.pos == len(sp.in) {
+			return list, nil
+		}
+		if sp.in[sp.pos] != ',' {
+			return nil, fmt.Errorf("unexpected %q at %d", sp.in[sp.pos], sp.pos)
+		}
+		sp.pos++
+	}
+}
+
+func (sp *selectorParser) parseComplex() (complexSelector, error) {
+	s := complexSelector{}
+	sp.skipWhitespace()
+	var combinator byte
+	for {
+		c, err := sp.parseCompound()
+		if err != nil {
+			return nil, err
+		}
+		c.combinator = combinator
+		s = append(s, c)
+		whitespace := sp.skipWhitespace()
+		if sp.pos == len(sp.in) || sp.in[sp.pos] == ',' {
+			return s, nil
+		}
+		switch {
+		case sp.in[sp.pos] == '>':
+			sp.pos++
+			sp.skipWhitespace()
+			combinator = '>'
+		case whitespace:
+			combinator = ' '
+		default:
+			return nil, fmt.Errorf("unexpected %q at %d", sp.in[sp.pos], sp.pos)
+		}
+	}
+}
+
+func (sp *selectorParser) parseCompound() (compoundSelector, error) {
+	c := compoundSelector{}
+	start := sp.pos
+	if sp.pos < len(sp.in) && sp.in[sp.pos] == '*' {
+		sp.pos++
+	} else if tag := sp.parseIdent(); tag != "" {
+		tag = strings.ToLower(tag)
+		c.matchers = append(c.matchers, func(n *html.Node) bool {
+			return n.Data == tag
+		})
+	}
+	for sp.pos < len(sp.in) {
+		var m func(n *html.Node) bool
+		var err error
+		switch sp.in[sp.pos] {
+		case '#':
+			sp.pos++
+			id := sp.parseIdent()
+			if id == "" {
+				return c, fmt.Errorf("expected id at %d", sp.pos)
+			}
+			m = func(n *html.Node) bool {
+				return attr(n, "id") == id
+			}
+		case '.':
+			sp.pos++
+			class := sp.parseIdent()
+			if class == "" {
+				return c, fmt.Errorf("expected class at %d", sp.pos)
+			}
+			m = func(n *html.Node) bool {
+				return includesWord(attr(n, "class"), class)
+			}
+		case '[':
+			m, err = sp.parseAttribute()
+		case ':':
+			m, err = sp.parsePseudo()
+		default:
+			if sp.pos == start {
+				return c, fmt.Errorf("unexpected %q at %d", sp.in[sp.pos], sp.pos)
+			}
+			return c, nil
+		}
+		if err != nil {
+			return c, err
+		}
+		c.matchers = append(c.matchers, m)
+	}
+	if sp.pos == start {
+		return c, fmt.Errorf("unexpected end")
+	}
+	return c, nil
+}
+
+func (sp *selectorParser) parseAttribute() (func(n *html.Node) bool, error) {
+	sp.pos++ // [
+	sp.skipWhitespace()
+	key := strings.ToLower(sp.parseIdent())
+	if key == "" {
+		return nil, fmt.Errorf("expected attribute at %d", sp.pos)
+	}
+	sp.skipWhitespace()
+	if sp.pos < len(sp.in) && sp.in[sp.pos] == ']' {
+		sp.pos++
+		return func(n *html.Node) bool {
+			for _, a := range n.Attr {
+				if a.Key == key {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+	op := ""
+	if sp.pos < len(sp.in) && strings.IndexByte("~|^$*", sp.in[sp.pos]) >= 0 {
+		op = sp.in[sp.pos : sp.pos+1]
+		sp.pos++
+	}
+	if sp.pos == len(sp.in) || sp.in[sp.pos] != '=' {
+		return nil, fmt.Errorf("expected = at %d", sp.pos)
+	}
+	sp.pos++
+	sp.skipWhitespace()
+	val, err := sp.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	sp.skipWhitespace()
+	if sp.pos == len(sp.in) || sp.in[sp.pos] != ']' {
+		return nil, fmt.Errorf("expected ] at %d", sp.pos)
+	}
+	sp.pos++
+	matches := map[string]func(string) bool{
+		"":  func(v string) bool { return v == val },
+		"~": func(v string) bool { return includesWord(v, val) },
+		"|": func(v string) bool {
+			return v == val || strings.HasPrefix(v, val+"-")
+		},
+		"^": func(v string) bool { return val != "" && strings.HasPrefix(v, val) },
+		"$": func(v string) bool { return val != "" && strings.HasSuffix(v, val) },
+		"*": func(v string) bool { return val != "" && strings.Contains(v, val) },
+	}[op]
+	return func(n *html.Node) bool {
+		for _, a := range n.Attr {
+			if a.Key == key {
+				return matches(a.Val)
+			}
+		}
+		return false
+	}, nil
+}
+
+func (sp *selectorParser) parsePseudo() (func(n *html.Node) bool, error) {
+	sp.pos++ // :
+	name := strings.ToLower(sp.parseIdent())
+	switch name {
+	case "first-child":
+		return func(n *html.Node) bool {
+			return elementIndex(n, false) == 1
+		}, nil
+	case "last-child":
+		return func(n *html.Node) bool {
+			for s := n.NextSibling; s != nil; s = s.NextSibling {
+				if s.Type == html.ElementNode {
+					return false
+				}
+			}
+			return true
+		}, nil
+	case "nth-child", "nth-of-type":
+		if sp.pos == len(sp.in) || sp.in[sp.pos] != '(' {
+			return nil, fmt.Errorf("expected ( at %d", sp.pos)
+		}
+		end := strings.IndexByte(sp.in[sp.pos:], ')')
+		if end < 0 {
+			return nil, fmt.Errorf("expected ) after %d", sp.pos)
+		}
+		arg := strings.TrimSpace(sp.in[sp.pos+1 : sp.pos+end])
+		sp.pos += end + 1
+		idx, err := strconv.Atoi(arg)
+		if err != nil || idx < 1 {
+			return nil, fmt.Errorf("only positive numbers are supported in :%s", name)
+		}
+		ofType := name == "nth-of-type"
+		return func(n *html.Node) bool {
+			return elementIndex(n, ofType) == idx
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported pseudo-class :%s", name)
+	}
+}
+
+func (sp *selectorParser) parseValue() (string, error) {
+	if sp.pos < len(sp.in) && (sp.in[sp.pos] == '"' || sp.in[sp.pos] == '\'') {
+		quote := sp.in[sp.pos]
+		end := strings.IndexByte(sp.in[sp.pos+1:], quote)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string at %d", sp.pos)
+		}
+		val := sp.in[sp.pos+1 : sp.pos+1+end]
+		sp.pos += end + 2
+		return val, nil
+	}
+	val := sp.parseIdent()
+	if val == "" {
+		return "", fmt.Errorf("expected value at %d", sp.pos)
+	}
+	return val, nil
+}
+
+func (sp *selectorParser) parseIdent() string {
+	start := sp.pos
+	for sp.pos < len(sp.in) {
+		b := sp.in[sp.pos]
+		if b == '-' || b == '_' || b >= 0x80 ||
+			(b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9') {
+			sp.pos++
+			continue
+		}
+		break
+	}
+	return sp.in[start:sp.pos]
+}
+
+func (sp *selectorParser) skipWhitespace() bool {
+	start := sp.pos
+	for sp.pos < len(sp.in) && isASCIIWhitespace(sp.in[sp.pos]) {
+		sp.pos++
+	}
+	return sp.pos > start
+}
+
+// elementIndex returns 1-based position of the element among its siblings
+func elementIndex(n *html.Node, ofType bool) int {
+	idx := 1
+	for s := n.PrevSibling; s != nil; s = s.PrevSibling {
+		if s.Type != html.ElementNode {
+			continue
+		}
+		if ofType && s.Data != n.Data {
+			continue
+		}
+		idx++
+	}
+	return idx
+}
+
+// includesWord is true if whitespace-separated list contains the word
+func includesWord(list, word string) bool {
+	for _, v := range strings.Fields(list) {
+		if v == word {
+			return true
+		}
+	}
+	return false
+}
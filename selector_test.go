@@ -0,0 +1,105 @@
+package htmltable
+
+import (
+	"testing"
+)
+
+const fixtureSelectors = `<body>
+<div class="results">
+	<table id="prices" data-kind="stock">
+		<tr><th>Ticker</th><th>Price</th></tr>
+		<tr><td>A</td><td>1</td></tr>
+	</table>
+	<section>
+		<table class="wide compact">
+			<tr><th>Ticker</th><th>Price</th></tr>
+			<tr><td>B</td><td>2</td></tr>
+		</table>
+	</section>
+</div>
+<table lang="en-US">
+	<tr><th>Ticker</th><th>Price</th></tr>
+	<tr><td>C</td><td>3</td></tr>
+</table>
+</body>`
+
+func TestFindBySelector(t *testing.T) {
+	p, err := NewFromString(fixtureSelectors)
+	assertNoError(t, err)
+	for selector, tickers := range map[string][]string{
+		"table":                        {"A", "B", "C"},
+		"table#prices":                 {"A"},
+		"#prices":                      {"A"},
+		"div.results table":            {"A", "B"},
+		"div.results > table":          {"A"},
+		"body > div > section > table": {"B"},
+		".compact":                     {"B"},
+		"table.wide.compact":           {"B"},
+		"[data-kind]":                  {"A"},
+		"[data-kind=stock]":            {"A"},
+		`[data-kind="stock"]`:          {"A"},
+		"[class~=wide]":                {"B"},
+		"[lang|=en]":                   {"C"},
+		"[id^=pri]":                    {"A"},
+		"[id$=ces]":                    {"A"},
+		"[class*=omp]":                 {"B"},
+		"table:first-child":            {"A", "B"},
+		"table:last-child":             {"B", "C"},
+		"body > :nth-child(2)":         {"C"},
+		"div :nth-of-type(1)":          {"A", "B"},
+		"#prices, [lang]":              {"A", "C"},
+		"span table":                   {},
+	} {
+		tables, err := p.FindBySelector(selector)
+		assertNoError(t, err)
+		found := []string{}
+		for _, table := range tables {
+			found = append(found, table.Rows[0][0])
+		}
+		if len(found) != len(tickers) {
+			t.Errorf("%s: %v (expected) != %v (got)", selector, tickers, found)
+			continue
+		}
+		assertEqual(t, tickers, found)
+	}
+}
+
+func TestFindBySelectorInvalid(t *testing.T) {
+	p, err := NewFromString(fixtureSelectors)
+	assertNoError(t, err)
+	for _, selector := range []string{
+		"",
+		"table >",
+		"table,",
+		"#",
+		"[id",
+		"[id!=x]",
+		":hover",
+		":nth-child(2n+1)",
+		"table !",
+	} {
+		_, err := p.FindBySelector(selector)
+		assertError(t, err)
+	}
+}
+
+func TestFindWithColumnsWithSelector(t *testing.T) {
+	p, err := NewFromString(fixtureSelectors, WithSelector("section table"))
+	assertNoError(t, err)
+	table, err := p.FindWithColumns("Ticker", "Price")
+	assertNoError(t, err)
+	assertEqual(t, [][]string{{"B", "2"}}, table.Rows)
+}
+
+func TestNewSliceWithSelector(t *testing.T) {
+	type price struct {
+		Ticker string `header:"Ticker"`
+		Price  int    `header:"Price"`
+	}
+	out, err := NewSliceFromString[price](fixtureSelectors, WithSelector("table[lang]"))
+	assertNoError(t, err)
+	assertEqual(t, []price{{"C", 3}}, out)
+
+	_, err = NewSliceFromString[price](fixtureSelectors, WithSelector("table["))
+	assertEqualError(t, err, "invalid selector `table[`: expected attribute at 6")
+}
@@ -6,48 +6,64 @@ import (
 	"io"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
 // NewSlice returns slice of annotated struct types from io.Reader
-func NewSlice[T any](ctx context.Context, r io.Reader) ([]T, error) {
+func NewSlice[T any](ctx context.Context, r io.Reader, opts ...Option) ([]T, error) {
 	f := &feeder[T]{
 		Page: Page{ctx: ctx},
 	}
+	f.opts.apply(opts)
 	f.init(r)
 	return f.slice()
 }
 
 // NewSliceFromPage finds a table matching the slice and returns the slice
-func NewSliceFromPage[T any](p *Page) ([]T, error) {
-	return (&feeder[T]{
+func NewSliceFromPage[T any](p *Page, opts ...Option) ([]T, error) {
+	f := &feeder[T]{
 		Page: *p,
-	}).slice()
+	}
+	f.opts.apply(opts)
+	return f.slice()
 }
 
 // NewSliceFromString is same as NewSlice(context.Context, io.Reader),
 // but takes just a string.
-func NewSliceFromString[T any](in string) ([]T, error) {
-	return NewSlice[T](context.Background(), strings.NewReader(in))
+func NewSliceFromString[T any](in string, opts ...Option) ([]T, error) {
+	return NewSlice[T](context.Background(), strings.NewReader(in), opts...)
 }
 
 // NewSliceFromString is same as NewSlice(context.Context, io.Reader),
 // but takes just an http.Response
-func NewSliceFromResponse[T any](resp *http.Response) ([]T, error) {
-	return NewSlice[T](resp.Request.Context(), resp.Body)
+func NewSliceFromResponse[T any](resp *http.Response, opts ...Option) ([]T, error) {
+	p, err := NewFromResponse(resp, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewSliceFromPage[T](p)
 }
 
 // NewSliceFromString is same as NewSlice(context.Context, io.Reader),
 // but takes just an URL.
-func NewSliceFromURL[T any](url string) ([]T, error) {
-	resp, err := http.Get(url)
+func NewSliceFromURL[T any](url string, opts ...Option) ([]T, error) {
+	return NewSliceFromURLContext[T](context.Background(), url, opts...)
+}
+
+// NewSliceFromURLContext is same as NewSliceFromURL, but the request
+// is bound to the context.
+func NewSliceFromURLContext[T any](ctx context.Context, url string, opts ...Option) ([]T, error) {
+	var o options
+	o.apply(opts)
+	resp, err := fetch(ctx, url, &o)
 	if err != nil {
 		return nil, err
 	}
 	if resp.Body != nil {
 		defer resp.Body.Close()
 	}
-	return NewSliceFromResponse[T](resp)
+	return NewSliceFromResponse[T](resp, opts...)
 }
 
 type feeder[T any] struct {
@@ -56,25 +72,108 @@ type feeder[T any] struct {
 	dummy T
 }
 
-func (f *feeder[T]) headers() ([]string, map[string]int, error) {
+// binding is the struct field annotated with the `header` tag, like
+// `header:"Security"` or `header:"Security,attr=href"`
+type binding struct {
+	header string
+	// column offset from `col` tag, or -1
+	col   int
+	field int
+	// attribute of the cell or the first element within it having one
+	attr string
+	// attribute of the first <img> within the cell
+	img string
+	// text of footnotes, that the cell refers to
+	footnote bool
+	// text of the row header, see Table.RowLabels
+	rowHeader bool
+	// label of the row group, see Table.Groups
+	group bool
+	// position of the table in Page.Tables, see Table.Sources
+	source bool
+}
+
+// parseTag splits the tag into header and options. Options are only
+// recognized from the end, so that headers with commas still work.
+func parseTag(tag string) (binding, error) {
+	b := binding{header: tag, col: -1}
+	for {
+		idx := strings.LastIndexByte(b.header, ',')
+		if idx < 0 {
+			return b, nil
+		}
+		key, value, hasValue := strings.Cut(b.header[idx+1:], "=")
+		key = strings.TrimSpace(key)
+		switch key {
+		case "footnote", "rowheader", "group", "source":
+			b.footnote = b.footnote || key == "footnote"
+			b.rowHeader = b.rowHeader || key == "rowheader"
+			b.group = b.group || key == "group"
+			b.source = b.source || key == "source"
+			if hasValue {
+				return b, fmt.Errorf("%s tag option has no value", key)
+			}
+			b.header = b.header[:idx]
+			continue
+		case "attr":
+			b.attr = strings.TrimSpace(value)
+		case "img":
+			b.img = strings.TrimSpace(value)
+		default:
+			return b, nil
+		}
+		if value == "" {
+			return b, fmt.Errorf("empty %s tag option", key)
+		}
+		b.header = b.header[:idx]
+	}
+}
+
+func (f *feeder[T]) bindings() ([]string, []binding, error) {
 	dt := reflect.ValueOf(f.dummy)
 	elem := dt.Type()
 	headers := []string{}
-	fields := map[string]int{}
+	seen := map[string]bool{}
+	bindings := []binding{}
 	for i := 0; i < elem.NumField(); i++ {
 		field := elem.Field(i)
-		header := field.Tag.Get("header")
-		if header == "" {
+		tag := field.Tag.Get("header")
+		col, byCol := field.Tag.Lookup("col")
+		if tag == "" && !byCol {
 			continue
 		}
 		err := f.isTypeSupported(field)
 		if err != nil {
 			return nil, nil, err
 		}
-		fields[header] = i
-		headers = append(headers, header)
+		if byCol {
+			tag = col
+		}
+		b, err := parseTag(tag)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", field.Name, err)
+		}
+		b.field = i
+		if b.rowHeader || b.group || b.source {
+			bindings = append(bindings, b)
+			continue
+		}
+		if byCol {
+			b.col, err = strconv.Atoi(b.header)
+			if err != nil || b.col < 0 {
+				return nil, nil, fmt.Errorf("%s: invalid column: %s", field.Name, b.header)
+			}
+			bindings = append(bindings, b)
+			continue
+		}
+		bindings = append(bindings, b)
+		if seen[b.header] {
+			continue
+		}
+		seen[b.header] = true
+		headers = append(headers, b.header)
 	}
-	return headers, fields, nil
+	return headers, bindings, nil
 }
 
 func (f *feeder[T]) isTypeSupported(field reflect.StructField) error {
@@ -92,63 +191,183 @@ func (f *feeder[T]) isTypeSupported(field reflect.StructField) error {
 		field.Name, field.Type.Name())
 }
 
-func (f *feeder[T]) table() (*Table, map[int]int, error) {
-	headers, fields, err := f.headers()
+// column is the binding resolved to the offset in table row
+type column struct {
+	binding
+	idx int
+}
+
+func (f *feeder[T]) table() (*Table, []column, error) {
+	headers, bindings, err := f.bindings()
 	if err != nil {
 		return nil, nil, err
 	}
-	table, err := f.FindWithColumns(headers...)
+	table, err := f.find(headers)
 	if err != nil {
 		return nil, nil, err
 	}
-	mapping := map[int]int{}
-	for idx, header := range table.Header {
-		field, ok := fields[header]
-		if !ok {
+	m := f.matcher()
+	columns := []column{}
+	for _, b := range bindings {
+		if b.rowHeader || b.group || b.source {
+			columns = append(columns, column{b, -1})
+			continue
+		}
+		if b.col >= 0 {
+			columns = append(columns, column{b, b.col})
+			continue
+		}
+		idx := m.offset(table.Header, b.header)
+		if idx < 0 {
 			continue
 		}
-		mapping[idx] = field
+		columns = append(columns, column{b, idx})
+	}
+	return table, columns, nil
+}
+
+// find returns the table having given columns, or all of them merged,
+// with Table.Sources set either way
+func (f *feeder[T]) find(headers []string) (*Table, error) {
+	if f.opts.merge {
+		found, err := f.findAll(headers)
+		if err != nil {
+			return nil, err
+		}
+		return f.merge(found), nil
+	}
+	table, err := f.FindWithColumns(headers...)
+	if err != nil {
+		return nil, err
+	}
+	source := 0
+	for idx := range f.Tables {
+		if f.Tables[idx] == table {
+			source = idx
+		}
+	}
+	single := *table
+	single.Sources = make([]int, len(single.Rows))
+	single.footerSources = make([]int, len(single.Footer))
+	for _, sources := range [][]int{single.Sources, single.footerSources} {
+		for i := range sources {
+			sources[i] = source
+		}
 	}
-	return table, mapping, nil
+	return &single, nil
+}
+
+// value returns either text or the attribute of the cell
+func (f *feeder[T]) value(c column, row []string, cells []*Cell, kind reflect.Kind) string {
+	var cell *Cell
+	if c.idx < len(cells) {
+		cell = cells[c.idx]
+	}
+	plain := c.attr == "" && c.img == "" && !c.footnote
+	if plain && kind == reflect.Int && f.opts.sortValues && cell != nil && cell.SortValue != "" {
+		return cell.SortValue
+	}
+	if plain {
+		return row[c.idx]
+	}
+	if cell == nil {
+		return ""
+	}
+	if c.footnote {
+		texts := []string{}
+		for _, fn := range cell.Footnotes {
+			if fn.Text == "" {
+				continue
+			}
+			texts = append(texts, fn.Text)
+		}
+		return strings.Join(texts, "\n")
+	}
+	key, v := c.attr, ""
+	if c.img != "" {
+		key, v = c.img, cell.imgAttr(c.img)
+	} else {
+		v = cell.findAttr(c.attr)
+	}
+	if key == "href" || key == "src" {
+		v = f.resolve(v)
+	}
+	return v
 }
 
 func (f *feeder[T]) slice() ([]T, error) {
-	table, mapping, err := f.table()
+	table, columns, err := f.table()
 	if err != nil {
 		return nil, err
 	}
+	rows, cells := table.Rows, table.Cells
+	labels, groups, sources := table.RowLabels, table.Groups, table.Sources
+	if f.opts.footer {
+		rows = append(rows[:len(rows):len(rows)], table.Footer...)
+		cells = append(cells[:len(cells):len(cells)], table.FooterCells...)
+		sources = append(sources[:len(sources):len(sources)], table.footerSources...)
+		for _, slots := range table.FooterCells {
+			labels = append(labels[:len(labels):len(labels)], rowLabel(slots))
+			groups = append(groups[:len(groups):len(groups)], "")
+		}
+	}
 	dummy := reflect.ValueOf(f.dummy)
 	dt := dummy.Type()
 	sliceValue := reflect.MakeSlice(reflect.SliceOf(dt),
-		len(table.Rows), len(table.Rows))
-	for rowIdx, row := range table.Rows {
+		len(rows), len(rows))
+	for rowIdx, row := range rows {
 		item := sliceValue.Index(rowIdx)
-		for idx, field := range mapping {
-			if len(row) < len(mapping) && idx == len(row) {
+		for _, c := range columns {
+			if c.idx >= len(row) {
 				// either corrupt row or something like that
 				continue
 			}
-			switch item.Field(field).Kind() {
+			var rowCells []*Cell
+			if rowIdx < len(cells) {
+				rowCells = cells[rowIdx]
+			}
+			field := item.Field(c.field)
+			var value string
+			switch {
+			case c.rowHeader && rowIdx < len(labels):
+				value = labels[rowIdx]
+			case c.group && rowIdx < len(groups):
+				value = groups[rowIdx]
+			case c.source && rowIdx < len(sources):
+				value = strconv.Itoa(sources[rowIdx])
+			case c.idx >= 0:
+				value = f.value(c, row, rowCells, field.Kind())
+			}
+			switch field.Kind() {
 			case reflect.String:
-				item.Field(field).SetString(row[idx])
+				field.SetString(value)
 			case reflect.Bool:
 				var v bool
-				lower := strings.ToLower(row[idx])
+				lower := strings.ToLower(value)
 				if lower == "yes" ||
 					lower == "y" ||
 					lower == "true" ||
 					lower == "t" {
 					v = true
 				}
-				item.Field(field).SetBool(v)
+				field.SetBool(v)
 			case reflect.Int:
 				var v int64
-				_, err := fmt.Sscan(row[idx], &v)
+				_, err := fmt.Sscan(value, &v)
 				if err != nil {
-					column := table.Header[idx]
+					column := "row header"
+					if c.group {
+						column = "group"
+					}
+					if c.source {
+						column = "source"
+					}
+					if c.idx >= 0 {
+						column = table.Header[c.idx]
+					}
 					return nil, fmt.Errorf("row %d: %s: %w", rowIdx, column, err)
 				}
-				item.Field(field).SetInt(v)
+				field.SetInt(v)
 			default: // noop
 			}
 		}
@@ -1,6 +1,7 @@
 package htmltable
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -436,3 +437,195 @@ const am4info = `<table class="wikitable" style="text-align:center">
    </tr>
 </tbody>
 </table>`
+
+type fruit struct {
+	Name  string `header:"Fruits Name"`
+	Count int    `header:"Fruits Count"`
+}
+
+func TestNewSliceSkipsFooter(t *testing.T) {
+	out, err := NewSliceFromString[fruit](fixtureSections)
+	assertNoError(t, err)
+	assertEqual(t, []fruit{
+		{"apple", 1},
+		{"pear", 2},
+		{"plum", 3},
+	}, out)
+}
+
+func TestNewSliceWithFooter(t *testing.T) {
+	type total struct {
+		Name string `header:"Fruits Name"`
+	}
+	out, err := NewSliceFromString[total](fixtureSections, WithFooter())
+	assertNoError(t, err)
+	assertEqual(t, []total{{"apple"}, {"pear"}, {"plum"}, {"Total"}}, out)
+}
+
+func TestNewSliceWithHeading(t *testing.T) {
+	type chipset struct {
+		Model string `header:"Model"`
+		TDP   int    `header:"TDP"`
+	}
+	out, err := NewSliceFromString[chipset](fixtureSameColumns, WithHeading("Desktop"))
+	assertNoError(t, err)
+	assertEqual(t, []chipset{{"X570", 15}}, out)
+}
+
+const fixtureLinks = `<table>
+	<tr><th>Security</th><th>Flag</th><th>Logo</th></tr>
+	<tr>
+		<td><a href="/wiki/3M" title="3M">3M</a></td>
+		<td><span class="flag" title="United States">US</span></td>
+		<td><img src="/img/3m.png" alt="3M logo"></td>
+	</tr>
+	<tr>
+		<td data-id="aapl"><a href="https://www.apple.com/">Apple</a></td>
+		<td>-</td>
+		<td></td>
+	</tr>
+</table>`
+
+type security struct {
+	Name    string `header:"Security"`
+	URL     string `header:"Security,attr=href"`
+	ID      string `header:"Security,attr=data-id"`
+	Country string `header:"Flag,attr=title"`
+	Logo    string `header:"Logo,img=src"`
+	LogoAlt string `header:"Logo,img=alt"`
+}
+
+func TestNewSliceBindsAttributes(t *testing.T) {
+	out, err := NewSliceFromString[security](fixtureLinks)
+	assertNoError(t, err)
+	assertEqual(t, []security{
+		{
+			Name:    "3M",
+			URL:     "/wiki/3M",
+			Country: "United States",
+			Logo:    "/img/3m.png",
+			LogoAlt: "3M logo",
+		},
+		{
+			Name: "Apple",
+			URL:  "https://www.apple.com/",
+			ID:   "aapl",
+		},
+	}, out)
+}
+
+func TestNewSliceResolvesRelativeURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(fixtureLinks))
+	}))
+	defer server.Close()
+	out, err := NewSliceFromURL[security](server.URL + "/wiki/List")
+	assertNoError(t, err)
+	assertEqual(t, server.URL+"/wiki/3M", out[0].URL)
+	assertEqual(t, server.URL+"/img/3m.png", out[0].Logo)
+	assertEqual(t, "https://www.apple.com/", out[1].URL)
+}
+
+func TestNewSliceResolvesBaseHref(t *testing.T) {
+	out, err := NewSliceFromString[security](`<head><base href="https://example.com/a/"></head>` + fixtureLinks)
+	assertNoError(t, err)
+	assertEqual(t, "https://example.com/wiki/3M", out[0].URL)
+}
+
+func TestNewSliceInvalidTagOption(t *testing.T) {
+	type invalid struct {
+		URL string `header:"Security,attr="`
+	}
+	_, err := NewSliceFromString[invalid](fixtureLinks)
+	assertEqualError(t, err, "URL: empty attr tag option")
+}
+
+func TestHeaderWithCommaIsNotTagOption(t *testing.T) {
+	type population struct {
+		Population int `header:"Population, 2020"`
+	}
+	out, err := NewSliceFromString[population](`<table>
+		<tr><th>Population, 2020</th></tr>
+		<tr><td>10</td></tr>
+	</table>`)
+	assertNoError(t, err)
+	assertEqual(t, []population{{10}}, out)
+}
+
+func TestNewSliceBindsColumnIndex(t *testing.T) {
+	type positional struct {
+		Name  string `col:"0"`
+		Count int    `col:"1"`
+	}
+	out, err := NewSliceFromString[positional](fixtureHeaderless, WithHeaderMode(HeaderNever))
+	assertNoError(t, err)
+	assertEqual(t, []positional{{"apple", 1}, {"pear", 2}}, out)
+
+	twoTables := fixtureHeaderless + `<table id="more"><tr><td>plum</td><td>3</td></tr></table>`
+	_, err = NewSliceFromString[positional](twoTables, WithHeaderMode(HeaderNever))
+	var ambiguous *AmbiguousTableError
+	assertEqual(t, true, errors.As(err, &ambiguous))
+	assertEqual(t, 2, len(ambiguous.Candidates))
+
+	out, err = NewSliceFromString[positional](twoTables, WithHeaderMode(HeaderNever), WithID("more"))
+	assertNoError(t, err)
+	assertEqual(t, []positional{{"plum", 3}}, out)
+
+	type invalid struct {
+		Name string `col:"first"`
+	}
+	_, err = NewSliceFromString[invalid](fixtureHeaderless)
+	assertEqualError(t, err, "Name: invalid column: first")
+}
+
+func TestNewSliceBindsRowHeader(t *testing.T) {
+	type country struct {
+		Name    string `header:",rowheader"`
+		Capital string `header:"Capital"`
+	}
+	out, err := NewSliceFromString[country](fixtureRowHeaders)
+	assertNoError(t, err)
+	assertEqual(t, []country{{"France", "Paris"}, {"Germany", "Berlin"}, {"", "Rome"}}, out)
+}
+
+func TestNewSliceBindsGroup(t *testing.T) {
+	type chipset struct {
+		Model   string `header:"Model"`
+		Segment string `header:",group"`
+	}
+	out, err := NewSliceFromString[chipset](fixtureGroups)
+	assertNoError(t, err)
+	assertEqual(t, chipset{"FCH", "Mobile chipsets"}, out[2])
+	assertEqual(t, chipset{"A320", ""}, out[3])
+}
+
+func TestNewSliceSkipsRepeatedHeaders(t *testing.T) {
+	type scorer struct {
+		Player string `header:"Player"`
+		Goals  int    `header:"Goals"`
+	}
+	out, err := NewSliceFromString[scorer](fixtureRepeatedHeaders)
+	assertNoError(t, err)
+	assertEqual(t, 3, len(out))
+
+	_, err = NewSliceFromString[scorer](fixtureRepeatedHeaders, WithRepeatedHeaders())
+	assertEqualError(t, err, "row 1: Goals: expected integer")
+}
+
+func TestNewSliceWithMerge(t *testing.T) {
+	type country struct {
+		Name   string `header:"Name"`
+		Source int    `header:",source"`
+	}
+	_, err := NewSliceFromString[country](fixtureSplitTables)
+	assertError(t, err)
+
+	out, err := NewSliceFromString[country](fixtureSplitTables, WithMerge())
+	assertNoError(t, err)
+	assertEqual(t, 6, len(out))
+	assertEqual(t, country{"Netherlands", 1}, out[2])
+
+	out, err = NewSliceFromString[country](fixtureSplitTables, WithID("missing"), WithMerge())
+	assertEqualError(t, err, "cannot find table with columns: Name")
+	assertEqual(t, 0, len(out))
+}
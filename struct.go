@@ -0,0 +1,77 @@
+package htmltable
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// NewStruct returns annotated struct type from io.Reader, filled from
+// the vertical table, like an infobox, where the first column holds
+// names matching `header` tags and the second column holds values.
+func NewStruct[T any](ctx context.Context, r io.Reader, opts ...Option) (*T, error) {
+	p, err := New(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewStructFromPage[T](p)
+}
+
+// NewStructFromPage finds a vertical table matching the struct and returns it
+func NewStructFromPage[T any](p *Page, opts ...Option) (*T, error) {
+	f := &feeder[T]{
+		Page: *p,
+	}
+	f.opts.apply(opts)
+	f.Tables = make([]*Table, len(p.Tables))
+	for idx, table := range p.Tables {
+		f.Tables[idx] = table.Transpose()
+	}
+	out, err := f.slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("table has no values")
+	}
+	return &out[0], nil
+}
+
+// NewStructFromString is same as NewStruct(context.Context, io.Reader),
+// but takes just a string.
+func NewStructFromString[T any](in string, opts ...Option) (*T, error) {
+	return NewStruct[T](context.Background(), strings.NewReader(in), opts...)
+}
+
+// NewStructFromResponse is same as NewStruct(context.Context, io.Reader),
+// but takes just an http.Response
+func NewStructFromResponse[T any](resp *http.Response, opts ...Option) (*T, error) {
+	p, err := NewFromResponse(resp, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewStructFromPage[T](p)
+}
+
+// NewStructFromURL is same as NewStruct(context.Context, io.Reader),
+// but takes just an URL.
+func NewStructFromURL[T any](url string, opts ...Option) (*T, error) {
+	return NewStructFromURLContext[T](context.Background(), url, opts...)
+}
+
+// NewStructFromURLContext is same as NewStructFromURL, but the request
+// is bound to the context.
+func NewStructFromURLContext[T any](ctx context.Context, url string, opts ...Option) (*T, error) {
+	var o options
+	o.apply(opts)
+	resp, err := fetch(ctx, url, &o)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	return NewStructFromResponse[T](resp, opts...)
+}
@@ -0,0 +1,55 @@
+package htmltable
+
+import "testing"
+
+const fixtureInfobox = `<body>
+<table class="wikitable">
+	<tr><th>Name</th><th>Born</th></tr>
+	<tr><td>Ada Lovelace</td><td>1815</td></tr>
+</table>
+<table class="infobox">
+	<tr><th colspan="2">Go</th></tr>
+	<tr><th>Designed by</th><td>Robert Griesemer<br>Rob Pike<br>Ken Thompson</td></tr>
+	<tr><th>First appeared</th><td>2009</td></tr>
+	<tr><th>Typing discipline</th><td>static, strong</td></tr>
+	<tr><th>Website</th><td><a href="https://go.dev">go.dev</a></td></tr>
+</table>
+</body>`
+
+type language struct {
+	Designers string `header:"Designed by"`
+	Appeared  int    `header:"First appeared"`
+	Website   string `header:"Website,attr=href"`
+}
+
+func TestNewStructFromString(t *testing.T) {
+	out, err := NewStructFromString[language](fixtureInfobox, WithTextMode(TextBlock))
+	assertNoError(t, err)
+	assertEqual(t, &language{
+		Designers: "Robert Griesemer\nRob Pike\nKen Thompson",
+		Appeared:  2009,
+		Website:   "https://go.dev",
+	}, out)
+}
+
+func TestNewStructNotFound(t *testing.T) {
+	type missing struct {
+		License string `header:"License"`
+	}
+	_, err := NewStructFromString[missing](fixtureInfobox)
+	assertEqualError(t, err, "cannot find table with columns: License; "+
+		"closest Table[Name, Ada Lovelace] (1 rows) misses: License")
+}
+
+func TestTranspose(t *testing.T) {
+	p, err := NewFromString(fixtureInfobox)
+	assertNoError(t, err)
+	table := p.Tables[1].Transpose()
+	assertEqual(t, []string{"Go", "Designed by", "First appeared", "Typing discipline", "Website"}, table.Header)
+	assertEqual(t, [][]string{{"Go", "Robert GriesemerRob PikeKen Thompson", "2009", "static, strong", "go.dev"}}, table.Rows)
+	assertEqual(t, "infobox", table.Class)
+
+	table = p.Tables[0].Transpose()
+	assertEqual(t, []string{"Name", "Ada Lovelace"}, table.Header)
+	assertEqual(t, [][]string{{"Born", "1815"}}, table.Rows)
+}
@@ -0,0 +1,203 @@
+package htmltable
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// TextMode controls how text of cells, captions and headings is extracted
+type TextMode int
+
+const (
+	// TextCompact trims every text node and concatenates them without
+	// any separator, so that "Processor<br>overclocking" becomes
+	// "Processoroverclocking". This is the default mode.
+	TextCompact TextMode = iota
+
+	// TextInline keeps whitespace between text nodes, collapsing its runs,
+	// including &nbsp;, into a single space. <br> and boundaries of block
+	// elements become spaces and content of <pre> is kept as is.
+	TextInline
+
+	// TextBlock is the same as TextInline, but <br> and boundaries of block
+	// elements, like <p>, <li> or <div>, become newlines.
+	TextBlock
+)
+
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"dd": true, "details": true, "dialog": true, "div": true, "dl": true,
+	"dt": true, "fieldset": true, "figcaption": true, "figure": true,
+	"footer": true, "form": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "header": true, "hr": true,
+	"li": true, "main": true, "nav": true, "ol": true, "p": true,
+	"pre": true, "section": true, "summary": true, "ul": true,
+	"td": true, "th": true, "tr": true, "caption": true,
+}
+
+func (p *Page) innerText(n *html.Node, sb *strings.Builder) {
+	var text strings.Builder
+	if p.opts.text == TextCompact {
+		p.compactText(n, &text)
+	} else {
+		tw := &textWriter{
+			sb:    &text,
+			block: p.opts.text == TextBlock,
+			skip:  p.skip,
+		}
+		tw.walk(n)
+	}
+	sb.WriteString(text.String())
+}
+
+// skip is true for elements, which text is not extracted
+func (p *Page) skip(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if p.opts.noFootnotes && (isFootnote(n) || p.isMarker(n)) {
+		return true
+	}
+	if p.opts.displayed && isHidden(n) {
+		return true
+	}
+	return false
+}
+
+// hiddenClasses are common classes for content, that is not displayed
+var hiddenClasses = []string{
+	"hidden", "sortkey", "d-none", "display-none",
+	"sr-only", "visually-hidden", "visuallyhidden",
+}
+
+// isHidden is true for elements, that are not displayed
+func isHidden(n *html.Node) bool {
+	for _, a := range n.Attr {
+		if a.Key == "hidden" {
+			return true
+		}
+	}
+	style := strings.ToLower(attr(n, "style"))
+	for _, decl := range strings.Split(style, ";") {
+		prop, value, ok := strings.Cut(decl, ":")
+		if !ok || strings.TrimSpace(prop) != "display" {
+			continue
+		}
+		value = strings.TrimSuffix(strings.TrimSpace(value), "!important")
+		if strings.TrimSpace(value) == "none" {
+			return true
+		}
+	}
+	class := attr(n, "class")
+	for _, hidden := range hiddenClasses {
+		if includesWord(class, hidden) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Page) compactText(n *html.Node, sb *strings.Builder) {
+	if n.Type == html.ElementNode && n.Data == "table" {
+		// nested tables are emitted on their own
+		return
+	}
+	if p.skip(n) {
+		return
+	}
+	if n.Type == html.TextNode {
+		sb.WriteString(strings.TrimSpace(n.Data))
+		return
+	}
+	if n.FirstChild == nil {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		p.compactText(c, sb)
+	}
+}
+
+// textWriter collapses whitespace, so that it is written only between
+// words and never at the beginning or the end of the text
+type textWriter struct {
+	sb    *strings.Builder
+	block bool
+	skip  func(n *html.Node) bool
+
+	written bool
+	space   bool
+	newline bool
+	pre     int
+}
+
+func (tw *textWriter) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		if tw.pre > 0 {
+			tw.flush()
+			tw.sb.WriteString(n.Data)
+			tw.written = true
+			return
+		}
+		for _, r := range n.Data {
+			// IsSpace includes U+00A0, so &nbsp; collapses as well
+			if unicode.IsSpace(r) {
+				tw.space = true
+				continue
+			}
+			tw.flush()
+			tw.sb.WriteRune(r)
+			tw.written = true
+		}
+		return
+	case html.ElementNode:
+		if tw.skip(n) {
+			return
+		}
+		switch n.Data {
+		case "table", "script", "style", "template":
+			// nested tables are emitted on their own
+			return
+		case "br":
+			tw.boundary()
+			return
+		case "pre":
+			tw.pre++
+			defer func() {
+				tw.pre--
+			}()
+		}
+	}
+	block := n.Type == html.ElementNode && blockElements[n.Data]
+	if block {
+		tw.boundary()
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		tw.walk(c)
+	}
+	if block {
+		tw.boundary()
+	}
+}
+
+// boundary of the line or the block
+func (tw *textWriter) boundary() {
+	if tw.block {
+		tw.newline = true
+		return
+	}
+	tw.space = true
+}
+
+// flush writes pending whitespace before the next character
+func (tw *textWriter) flush() {
+	if tw.written && tw.newline {
+		tw.sb.WriteByte('\n')
+	} else if tw.written && tw.space {
+		tw.sb.WriteByte(' ')
+	}
+	tw.newline = false
+	tw.space = false
+}
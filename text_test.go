@@ -0,0 +1,89 @@
+package htmltable
+
+import (
+	"testing"
+)
+
+const fixtureText = `<table>
+	<tr>
+		<th><a href="/wiki/PCI_Express">PCIe</a> support<sup class="reference">[a]</sup></th>
+		<th>Processor<br><a href="/wiki/Overclocking">overclocking</a></th>
+		<th>Notes</th>
+	</tr>
+	<tr>
+		<td>~5&nbsp;W  </td>
+		<td>Yes,<br>with <abbr>PBO</abbr></td>
+		<td><p>first</p><ul><li>a</li><li>b</li></ul><div>c  <style>.x{}</style></div><pre>  x
+  y</pre></td>
+	</tr>
+</table>`
+
+func TestTextModes(t *testing.T) {
+	for _, tt := range []struct {
+		mode   TextMode
+		header []string
+		row    []string
+	}{
+		{
+			mode:   TextCompact,
+			header: []string{"PCIesupport[a]", "Processoroverclocking", "Notes"},
+			row:    []string{"~5\u00a0W", "Yes,withPBO", "firstabc.x{}x\n  y"},
+		},
+		{
+			mode:   TextInline,
+			header: []string{"PCIe support[a]", "Processor overclocking", "Notes"},
+			row:    []string{"~5 W", "Yes, with PBO", "first a b c   x\n  y"},
+		},
+		{
+			mode:   TextBlock,
+			header: []string{"PCIe support[a]", "Processor\noverclocking", "Notes"},
+			row:    []string{"~5 W", "Yes,\nwith PBO", "first\na\nb\nc\n  x\n  y"},
+		},
+	} {
+		p, err := NewFromString(fixtureText, WithTextMode(tt.mode))
+		assertNoError(t, err)
+		assertEqual(t, tt.header, p.Tables[0].Header)
+		assertEqual(t, tt.row, p.Tables[0].Rows[0])
+	}
+}
+
+const fixtureHidden = `<table>
+	<tr><th>City</th><th>Population</th></tr>
+	<tr>
+		<td><span class="sortkey">Berlin !</span>Berlin</td>
+		<td data-sort-value="3645000"><span style="display: none">0000003645000</span> 3,645,000</td>
+	</tr>
+	<tr>
+		<td>Hamburg<span hidden>x</span></td>
+		<td><span data-sort-value="1841000"><span style="DISPLAY:none !important">1841</span>1,841,000</span></td>
+	</tr>
+</table>`
+
+func TestDisplayedOnly(t *testing.T) {
+	p, err := NewFromString(fixtureHidden)
+	assertNoError(t, err)
+	assertEqual(t, []string{"Berlin !Berlin", "00000036450003,645,000"}, p.Tables[0].Rows[0])
+
+	p, err = NewFromString(fixtureHidden, WithDisplayedOnly())
+	assertNoError(t, err)
+	assertEqual(t, [][]string{
+		{"Berlin", "3,645,000"},
+		{"Hamburg", "1,841,000"},
+	}, p.Tables[0].Rows)
+	assertEqual(t, "3645000", p.Tables[0].Cells[0][1].SortValue)
+	assertEqual(t, "1841000", p.Tables[0].Cells[1][1].SortValue)
+}
+
+func TestNewSliceWithSortValues(t *testing.T) {
+	type city struct {
+		City       string `header:"City"`
+		Population int    `header:"Population"`
+	}
+	out, err := NewSliceFromString[city](fixtureHidden, WithDisplayedOnly(), WithSortValues())
+	assertNoError(t, err)
+	assertEqual(t, []city{{"Berlin", 3645000}, {"Hamburg", 1841000}}, out)
+
+	out, err = NewSliceFromString[city](fixtureHidden, WithDisplayedOnly())
+	assertNoError(t, err)
+	assertEqual(t, []city{{"Berlin", 3}, {"Hamburg", 1}}, out)
+}